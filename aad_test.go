@@ -0,0 +1,64 @@
+package otp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTOTPCodeAAD(t *testing.T) {
+	cfg := Config{Digits: EightDigits}
+	key := []byte("12345678901234567890")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	if code := TOTPCodeAAD(cfg, key, nil, testTime); code != 7081804 {
+		t.Errorf("Code without aad did not match. Expected 7081804 and got %d.\n", code)
+	}
+
+	first := TOTPCodeAAD(cfg, key, []byte("amount=100;to=alice"), testTime)
+	second := TOTPCodeAAD(cfg, key, []byte("amount=100;to=mallory"), testTime)
+	if first == second || first == 7081804 {
+		t.Errorf("Codes for different aad matched: %d and %d", first, second)
+	}
+	if again := TOTPCodeAAD(cfg, key, []byte("amount=100;to=alice"), testTime); again != first {
+		t.Errorf("Code did not match. Expected %d and got %d.\n", first, again)
+	}
+}
+
+func TestValidateAAD(t *testing.T) {
+	key := []byte("12345678901234567890")
+	aad := []byte("amount=100;to=alice")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	cfg := Config{Digits: EightDigits}
+
+	tests := []struct {
+		Name  string
+		AAD   []byte
+		Code  int
+		Match bool
+		T     int
+	}{
+		{"Match", aad, TOTPCodeAAD(cfg, key, aad, testTime), true, 0x23523EC},
+		{"Previous Step", aad, TOTPCodeAAD(cfg, key, aad, testTime.Add(-30*time.Second)), true, 0x23523EB},
+		{"Other AAD", []byte("amount=100;to=mallory"), TOTPCodeAAD(cfg, key, aad, testTime), false, 0x23523EC},
+		{"Standard Code", aad, 7081804, false, 0x23523EC},
+		{"Empty AAD", nil, 7081804, true, 0x23523EC},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:           key,
+				Digits:        EightDigits,
+				PastTolerance: 30 * time.Second,
+			}
+
+			match, tMatch := validator.ValidateAAD(testTime, test.AAD, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package otp
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValidateForAccount validates code for accountID with the key and Config returned by
+// keyFn, which is called once per validation so the key is only held for its duration.
+// The key returned by keyFn is used in place of the Config's Key. Codes are accepted from
+// one time step before to one time step after now, the transmission delay RFC 6238
+// recommends allowing for. Steps at or before lastT are rejected as for
+// TOTPValidator.LastT. On success the caller should store the returned T and pass it as
+// lastT on the account's next validation so a code can't be reused. An error is returned
+// if keyFn does.
+func ValidateForAccount(accountID string, now time.Time, code int, lastT int, keyFn func(id string) ([]byte, Config, error)) (bool, int, error) {
+	key, cfg, err := keyFn(accountID)
+	if err != nil {
+		return false, 0, fmt.Errorf("otp: fetching key for account %q: %w", accountID, err)
+	}
+
+	cfg.Key = key
+	stepSize := time.Duration(cfg.stepSizeSeconds()) * time.Second
+	validator := cfg.Validator(stepSize, stepSize)
+	validator.LastT = lastT
+	ok, t := validator.ValidateTOTPCode(now, code)
+	return ok, t, nil
+}
@@ -0,0 +1,61 @@
+package otp
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateForAccount(t *testing.T) {
+	errUnknown := errors.New("unknown account")
+	keyFn := func(id string) ([]byte, Config, error) {
+		switch id {
+		case "alice":
+			return []byte("12345678901234567890"), Config{Digits: EightDigits}, nil
+		case "bob":
+			return []byte("12345678901234567890123456789012"), Config{HashProvider: sha256.New, Digits: EightDigits}, nil
+		case "carol":
+			return nil, Config{}, nil
+		}
+		return nil, Config{}, errUnknown
+	}
+
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name    string
+		Account string
+		Code    int
+		LastT   int
+		Match   bool
+		T       int
+		Err     error
+	}{
+		{"Match", "alice", 7081804, 0, true, 0x23523EC, nil},
+		{"Previous Step", "alice", 89731029, 0, true, 0x23523EB, nil},
+		{"Next Step", "alice", 14050471, 0, true, 0x23523ED, nil},
+		{"Two Steps Ahead", "alice", 44266759, 0, false, 0x23523EC, nil},
+		{"After LastT", "alice", 14050471, 0x23523EC, true, 0x23523ED, nil},
+		{"Replayed", "alice", 7081804, 0x23523EC, false, 0x23523EC, nil},
+		{"Account Config", "bob", 68084774, 0, true, 0x23523EC, nil},
+		{"Other Account's Code", "bob", 7081804, 0, false, 0x23523EC, nil},
+		{"Empty Key", "carol", 7081804, 0, false, 0x23523EC, nil},
+		{"Unknown Account", "dave", 7081804, 0, false, 0, errUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			match, tMatch, err := ValidateForAccount(test.Account, testTime, test.Code, test.LastT, keyFn)
+			if !errors.Is(err, test.Err) {
+				t.Errorf("Error did not match. Expected %v and got %v.\n", test.Err, err)
+			}
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package otp
+
+import (
+	"hash"
+)
+
+// HOTPCodeAlphabet generates a HMAC-Based One-Time Password like HOTPCode but written as
+// length characters of alphabet rather than decimal digits. The 31 bit truncated value is
+// written as a number in base len(alphabet), most significant character first and padded
+// with alphabet[0], so HOTPCode is the special case of the decimal alphabet "0123456789":
+// HOTPCodeAlphabet(h, key, []rune("0123456789"), 6, v) is HOTPCode(h, key, SixDigits, v)
+// padded to six digits. Characters beyond the 31 bits of the value are always alphabet[0].
+// Steam Guard codes use the same reduction but write the least significant character first.
+// An empty string is returned if alphabet has fewer than two characters or length is less
+// than one. HOTPCodeAlphabet panics for a nil hashProvider or an empty key as HOTPCode does.
+func HOTPCodeAlphabet(hashProvider func() hash.Hash, key []byte, alphabet []rune, length int, value int64) string {
+	if len(alphabet) < 2 || length < 1 {
+		return ""
+	}
+	switch {
+	case hashProvider == nil:
+		panic(ErrNilHashProvider)
+	case len(key) == 0:
+		panic(ErrEmptyKey)
+	}
+
+	v := truncate(hashProvider, key, value)
+
+	base := uint32(len(alphabet))
+	code := make([]rune, length)
+	for i := length - 1; i >= 0; i-- {
+		code[i] = alphabet[v%base]
+		v /= base
+	}
+	return string(code)
+}
@@ -0,0 +1,65 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"testing"
+)
+
+func TestHOTPCodeAlphabetDecimal(t *testing.T) {
+	key := []byte("12345678901234567890")
+	decimal := []rune("0123456789")
+
+	for i, vector := range rfc4226Vectors {
+		code := HOTPCodeAlphabet(sha1.New, key, decimal, 6, int64(i))
+		expected := FormatCode(vector, SixDigits, FormatOptions{Pad: true})
+		if code != expected {
+			t.Errorf("Code did not match for %d. Expected %s and got %s.\n", i, expected, code)
+		}
+	}
+
+	if code := HOTPCodeAlphabet(sha1.New, key, decimal, 10, 0); code != "1284755224" {
+		t.Errorf("Ten digit code did not match. Expected 1284755224 and got %s.\n", code)
+	}
+}
+
+func TestHOTPCodeAlphabet(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name     string
+		Alphabet string
+		Length   int
+		Code     string
+	}{
+		// the truncated value at counter 0 is 1284755224
+		{"Hexadecimal", "0123456789abcdef", 8, "4c93cf18"},
+		{"Binary Suffix", "01", 4, "1000"},
+		{"Padded", "0123456789", 12, "001284755224"},
+		{"Multibyte", "αβγδεζηθικ", 3, "γγε"},
+		{"Steam", steamAlphabet, 5, "5F5GG"},
+		{"Single Character", "0", 6, ""},
+		{"Zero Length", "0123456789", 0, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code := HOTPCodeAlphabet(sha1.New, key, []rune(test.Alphabet), test.Length, 0)
+			if code != test.Code {
+				t.Errorf("Code did not match. Expected %s and got %s.\n", test.Code, code)
+			}
+		})
+	}
+}
+
+func TestHOTPCodeAlphabetSteam(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	code := []rune(HOTPCodeAlphabet(sha1.New, key, []rune(steamAlphabet), steamCodeLength, 0x23523EC))
+	for i, j := 0, len(code)-1; i < j; i, j = i+1, j-1 {
+		code[i], code[j] = code[j], code[i]
+	}
+
+	if steam := steamCode(key, 0x23523EC); string(code) != steam {
+		t.Errorf("Reversed code did not match. Expected %s and got %s.\n", steam, string(code))
+	}
+}
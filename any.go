@@ -0,0 +1,33 @@
+package otp
+
+import (
+	"time"
+)
+
+// ValidateAny validates each of codes like ValidateTOTPCode, returning whether any matched,
+// the index in codes of the first that did and the time step it matched. The returned T
+// has the same meaning as for ValidateTOTPCode and when nothing matches the index is -1.
+// Every code is checked whether or not an earlier one matched, and only the first match
+// is consumed, as for ValidateStringCT, and recorded with LastSuccess, as a single
+// validation.
+func (tc *TOTPValidator) ValidateAny(now time.Time, codes ...int) (bool, int, int) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	index := -1
+	tMatch := tc.stepAt(tc.stepSize(), now)
+	for i, code := range codes {
+		ok, t := tc.validate(now, code)
+		if ok && index < 0 {
+			index = i
+			tMatch = t
+		}
+	}
+
+	if index < 0 {
+		return false, -1, tMatch
+	}
+
+	tc.recordUse(now, tMatch)
+	return true, index, tMatch
+}
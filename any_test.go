@@ -0,0 +1,74 @@
+package otp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateAny(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Codes []int
+		LastT int
+		Match bool
+		Index int
+		T     int
+	}{
+		{"Single", []int{7081804}, 0, true, 0, 0x23523EC},
+		{"Second", []int{12345678, 89731029}, 0, true, 1, 0x23523EB},
+		{"First Of Several", []int{12345678, 14050471, 7081804}, 0, true, 1, 0x23523ED},
+		{"LastT", []int{89731029, 7081804}, 0x23523EB, true, 1, 0x23523EC},
+		{"No Match", []int{12345678, 23456789}, 0, false, -1, 0x23523EC},
+		{"All Used", []int{89731029, 7081804}, 0x23523EC, false, -1, 0x23523EC},
+		{"None", nil, 0, false, -1, 0x23523EC},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+				LastT:           test.LastT,
+			}
+
+			testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+			match, index, tMatch := validator.ValidateAny(testTime, test.Codes...)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if index != test.Index {
+				t.Errorf("Index did not match. Expected %d and got %d.\n", test.Index, index)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
+
+func TestValidateAnyMarksMatch(t *testing.T) {
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	validator := &TOTPValidator{
+		Key:           []byte("12345678901234567890"),
+		Digits:        EightDigits,
+		PastTolerance: 30 * time.Second,
+		ReplayStore:   NewReplayCache(10),
+	}
+
+	if ok, _, _ := validator.ValidateAny(testTime, 89731029, 7081804); !ok {
+		t.Fatal("Codes did not match")
+	}
+
+	if !validator.ReplayStore.Seen(0x23523EB) {
+		t.Error("Matched step was not marked")
+	}
+	if validator.ReplayStore.Seen(0x23523EC) {
+		t.Error("Step of a later code was marked")
+	}
+	if !validator.LastSuccess.Equal(testTime) {
+		t.Errorf("LastSuccess did not match. Expected %v and got %v.\n", testTime, validator.LastSuccess)
+	}
+}
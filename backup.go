@@ -0,0 +1,90 @@
+package otp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// GenerateBackupCodes returns count distinct random numeric backup codes of digits digits
+// each, zero-padded, for printing as single use recovery codes. Each digit is drawn
+// uniformly from crypto/rand by rejection sampling. An error is returned if count or
+// digits is not positive or more codes are requested than digits can distinguish.
+func GenerateBackupCodes(count, digits int) ([]string, error) {
+	return generateBackupCodes(rand.Reader, count, digits)
+}
+
+func generateBackupCodes(random io.Reader, count, digits int) ([]string, error) {
+	if count < 1 || digits < 1 {
+		return nil, errors.New("otp: backup code count and digits must be positive")
+	}
+
+	possible := uint64(1)
+	for i := 0; i < digits && possible <= uint64(count); i++ {
+		possible *= 10
+	}
+	if uint64(count) > possible {
+		return nil, fmt.Errorf("otp: %d digits can't produce %d distinct backup codes", digits, count)
+	}
+
+	codes := make([]string, 0, count)
+	seen := make(map[string]bool, count)
+	for len(codes) < count {
+		code, err := randomDigits(random, digits)
+		if err != nil {
+			return nil, fmt.Errorf("otp: generating backup codes: %w", err)
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+// randomDigits returns n uniformly random decimal digits. Bytes of 250 and above are
+// rejected so every digit is equally likely.
+func randomDigits(random io.Reader, n int) (string, error) {
+	digits := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(digits) < n {
+		if _, err := io.ReadFull(random, buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if b < 250 && len(digits) < n {
+				digits = append(digits, '0'+b%10)
+			}
+		}
+	}
+	return string(digits), nil
+}
+
+// VerifyBackupCode returns whether code matches one of the unused backup codes in codes
+// and consumes it by setting its entry to the empty string, so the caller should persist
+// codes after a match. Spaces and dashes in code are ignored as for ParseCode. Every
+// entry is compared in constant time so timing does not reveal which code matched.
+func VerifyBackupCode(codes []string, code string) bool {
+	input, err := codeDigits(code)
+	if err != nil {
+		return false
+	}
+
+	matched := 0
+	index := 0
+	for i, candidate := range codes {
+		isMatch := subtle.ConstantTimeCompare([]byte(candidate), []byte(input))
+		index = subtle.ConstantTimeSelect(isMatch&^matched, i, index)
+		matched |= isMatch
+	}
+
+	if matched == 0 {
+		return false
+	}
+	codes[index] = ""
+	return true
+}
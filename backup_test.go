@@ -0,0 +1,124 @@
+package otp
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestGenerateBackupCodes(t *testing.T) {
+	codes, err := GenerateBackupCodes(10, 8)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	if len(codes) != 10 {
+		t.Fatalf("Count did not match. Expected 10 and got %d.\n", len(codes))
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 8 || strings.Trim(code, "0123456789") != "" {
+			t.Errorf("Code %q is not 8 digits.\n", code)
+		}
+		if seen[code] {
+			t.Errorf("Code %q was repeated.\n", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestGenerateBackupCodesAllValues(t *testing.T) {
+	codes, err := GenerateBackupCodes(10, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	sort.Strings(codes)
+	expected := []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}
+	if !reflect.DeepEqual(codes, expected) {
+		t.Errorf("Codes did not match. Expected %v and got %v.\n", expected, codes)
+	}
+}
+
+func TestGenerateBackupCodesRejectionSampling(t *testing.T) {
+	// 250 and above would bias the digits towards 0 to 5 and are skipped
+	random := bytes.NewReader([]byte{250, 255, 3, 249, 10, 123, 7, 99})
+
+	codes, err := generateBackupCodes(random, 1, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if expected := []string{"3903"}; !reflect.DeepEqual(codes, expected) {
+		t.Errorf("Codes did not match. Expected %v and got %v.\n", expected, codes)
+	}
+}
+
+func TestGenerateBackupCodesErrors(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Count  int
+		Digits int
+	}{
+		{"Zero Count", 0, 8},
+		{"Negative Count", -1, 8},
+		{"Zero Digits", 10, 0},
+		{"Too Many Codes", 11, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if codes, err := GenerateBackupCodes(test.Count, test.Digits); err == nil {
+				t.Errorf("Expected an error and got %v.\n", codes)
+			}
+		})
+	}
+
+	if _, err := generateBackupCodes(bytes.NewReader([]byte{1, 2}), 1, 4); err == nil {
+		t.Error("Expected an error when random data runs out")
+	}
+}
+
+func TestVerifyBackupCode(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Code  string
+		Match bool
+		Codes []string
+	}{
+		{"Match", "23456789", true, []string{"12345678", "", "34567890"}},
+		{"Formatted", " 2345-6789 ", true, []string{"12345678", "", "34567890"}},
+		{"No Match", "23456780", false, []string{"12345678", "23456789", "34567890"}},
+		{"Prefix", "2345678", false, []string{"12345678", "23456789", "34567890"}},
+		{"Empty", "", false, []string{"12345678", "23456789", "34567890"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			codes := []string{"12345678", "23456789", "34567890"}
+
+			if match := VerifyBackupCode(codes, test.Code); match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if !reflect.DeepEqual(codes, test.Codes) {
+				t.Errorf("Codes did not match. Expected %v and got %v.\n", test.Codes, codes)
+			}
+		})
+	}
+}
+
+func TestVerifyBackupCodeSingleUse(t *testing.T) {
+	codes := []string{"12345678", "23456789"}
+
+	if !VerifyBackupCode(codes, "12345678") {
+		t.Error("Code did not match")
+	}
+	if VerifyBackupCode(codes, "12345678") {
+		t.Error("Consumed code matched")
+	}
+	if !VerifyBackupCode(codes, "23456789") {
+		t.Error("Remaining code did not match")
+	}
+}
@@ -0,0 +1,61 @@
+package otp
+
+import (
+	"sort"
+	"time"
+)
+
+// ValidateBetween returns a bool indicating if code is valid for some time between earliest
+// and latest. It is for when the current time is only known to lie within an interval, for
+// example between two readings of a slewing clock. The window extends from the past
+// tolerance before earliest to the future tolerance after latest, or covers the
+// AcceptOffsets of every time step in the interval. LastT and ReplayStore are honored, an
+// accepted step is consumed as for ValidateStringCT and recorded with LastSuccess and
+// the returned T has the same meaning as for ValidateTOTPCode, with the current step taken
+// at latest. As for ValidateTOTPCode every step is compared in constant time. The window
+// is capped at MaxWindowSteps steps nearest the middle of the interval so a wide interval
+// can't make validation arbitrarily expensive. With AcceptOffsets the offsets are applied
+// to at most MaxWindowSteps steps of the interval.
+func (tc *TOTPValidator) ValidateBetween(earliest, latest time.Time, code int) (bool, int) {
+	if latest.Before(earliest) {
+		earliest, latest = latest, earliest
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	steps := tc.stepsBetween(earliest, latest)
+	ok, t := tc.match(tc.generators(earliest), steps, code, tc.stepAt(tc.stepSize(), latest))
+	if ok {
+		tc.recordUse(latest, t)
+	}
+	return ok, t
+}
+
+// stepsBetween returns the time steps accepted at any time between earliest and latest in
+// ascending order, ignoring LastT, capped around the middle of the interval.
+func (tc *TOTPValidator) stepsBetween(earliest, latest time.Time) []int {
+	stepSize := tc.stepSize()
+	middle := tc.stepAt(stepSize, earliest.Add(latest.Sub(earliest)/2))
+
+	if len(tc.AcceptOffsets) > 0 {
+		first, last := tc.clampWindow(middle, tc.stepAt(stepSize, earliest), tc.stepAt(stepSize, latest))
+
+		var steps []int
+		seen := make(map[int]bool)
+		for c := first; c <= last; c++ {
+			for _, t := range tc.stepsAt(stepSize, tc.stepBegins(stepSize, c)) {
+				if !seen[t] {
+					seen[t] = true
+					steps = append(steps, t)
+				}
+			}
+		}
+		sort.Ints(steps)
+		return steps
+	}
+
+	tMin, _ := tc.window(stepSize, earliest)
+	_, tMax := tc.window(stepSize, latest)
+	return stepRange(tc.clampWindow(middle, tMin, tMax))
+}
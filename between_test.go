@@ -0,0 +1,147 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"hash"
+	"testing"
+	"time"
+)
+
+func TestValidateBetween(t *testing.T) {
+	earliest := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name            string
+		Latest          time.Time
+		Code            int
+		PastTolerance   int
+		FutureTolerance int
+		Offsets         []int
+		LastT           int
+		Match           bool
+		T               int
+	}{
+		{"T Same Instant", earliest, 7081804, 0, 0, nil, 0, true, 0x23523EC},
+		{"T+1 Same Instant", earliest, 14050471, 0, 0, nil, 0, false, 0x23523EC},
+		{"T+1 Spanning Boundary", earliest.Add(2 * time.Second), 14050471, 0, 0, nil, 0, true, 0x23523ED},
+		{"T Spanning Boundary", earliest.Add(2 * time.Second), 7081804, 0, 0, nil, 0, true, 0x23523EC},
+		{"T+2 Spanning Boundary", earliest.Add(2 * time.Second), 44266759, 0, 0, nil, 0, false, 0x23523ED},
+		{"T+2 Spanning Boundary Future", earliest.Add(2 * time.Second), 44266759, 0, 30, nil, 0, true, 0x23523EE},
+		{"T-1 Spanning Boundary Past", earliest.Add(2 * time.Second), 89731029, 30, 0, nil, 0, true, 0x23523EB},
+		{"T-1 Reversed", earliest.Add(-30 * time.Second), 89731029, 0, 0, nil, 0, true, 0x23523EB},
+		{"T+2 Offsets", earliest.Add(2 * time.Second), 44266759, 0, 0, []int{1}, 0, true, 0x23523EE},
+		{"T Offsets", earliest.Add(2 * time.Second), 7081804, 0, 0, []int{1}, 0, false, 0x23523ED},
+		{"T LastT", earliest.Add(2 * time.Second), 7081804, 0, 0, nil, 0x23523EC, false, 0x23523ED},
+		{"T+1 LastT", earliest.Add(2 * time.Second), 14050471, 0, 0, nil, 0x23523EC, true, 0x23523ED},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastTolerance:   time.Duration(test.PastTolerance) * time.Second,
+				FutureTolerance: time.Duration(test.FutureTolerance) * time.Second,
+				AcceptOffsets:   test.Offsets,
+				LastT:           test.LastT,
+			}
+
+			match, tMatch := validator.ValidateBetween(earliest, test.Latest, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
+
+func TestValidateBetweenScansFullWindow(t *testing.T) {
+	earliest := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name  string
+		Code  int
+		Match bool
+		T     int
+	}{
+		{"Match First Step", 89731029, true, 0x23523EB},
+		{"Match Middle Step", 7081804, true, 0x23523EC},
+		{"Match Last Step", 44266759, true, 0x23523EE},
+		{"No Match", 7081803, false, 0x23523ED},
+	}
+
+	counts := make(map[int]bool)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sums := 0
+			validator := &TOTPValidator{
+				Key: []byte("12345678901234567890"),
+				HashProvider: func() hash.Hash {
+					return summingHash{sha1.New(), &sums}
+				},
+				Digits:          EightDigits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+			}
+
+			match, tMatch := validator.ValidateBetween(earliest, earliest.Add(2*time.Second), test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+			counts[sums] = true
+		})
+	}
+
+	if len(counts) != 1 {
+		t.Errorf("Validation cost varied: %v", counts)
+	}
+}
+
+func TestValidateBetweenBoundsWork(t *testing.T) {
+	earliest := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	latest := earliest.Add(30 * 24 * time.Hour)
+	middle := 0x23523EC + 30*24*60*2/2
+
+	tests := []struct {
+		Name    string
+		Offsets []int
+		HMACs   int
+	}{
+		{"Window", nil, DefaultMaxWindowSteps},
+		{"Offsets", []int{-1, 0}, DefaultMaxWindowSteps + 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sums := 0
+			validator := &TOTPValidator{
+				Key: []byte("12345678901234567890"),
+				HashProvider: func() hash.Hash {
+					return summingHash{sha1.New(), &sums}
+				},
+				Digits:          EightDigits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+				AcceptOffsets:   test.Offsets,
+			}
+
+			if ok, _ := validator.ValidateBetween(earliest, latest, 7081804); ok {
+				t.Error("Code from the start of the interval matched")
+			}
+			// each HMAC sums the inner and outer hash
+			if hmacs := sums / 2; hmacs != test.HMACs {
+				t.Errorf("HMAC computations did not match. Expected %d and got %d.\n", test.HMACs, hmacs)
+			}
+
+			code := HOTPCode(sha1.New, validator.Key, EightDigits, int64(middle))
+			if ok, tMatch := validator.ValidateBetween(earliest, latest, code); !ok || tMatch != middle {
+				t.Errorf("Middle of the interval did not match. Expected true, %d and got %t, %d.\n", middle, ok, tMatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package otp
+
+import (
+	"sort"
+	"time"
+)
+
+// CodeIndex holds every code accepted by a TOTPValidator during a single time step.
+// Looking a code up in the index avoids recomputing the HMAC for each step in the
+// tolerance window on every validation.
+type CodeIndex struct {
+	T     int           // time step the index was built for
+	keys  int           // number of keys the index was built with
+	codes map[int][]int // code -> accepted time steps in ascending order
+}
+
+// NewCodeIndex precomputes the codes tc accepts at now.
+func (tc *TOTPValidator) NewCodeIndex(now time.Time) *CodeIndex {
+	steps := tc.steps(now)
+	generators := tc.generators(now)
+	index := &CodeIndex{
+		T:     tc.stepAt(tc.stepSize(), now),
+		keys:  len(tc.keys(now)),
+		codes: make(map[int][]int, len(steps)*len(generators)),
+	}
+	for _, g := range generators {
+		for _, t := range steps {
+			code := g.code(int64(t))
+			index.codes[code] = append(index.codes[code], t)
+		}
+	}
+	if len(generators) > 1 {
+		for _, accepted := range index.codes {
+			sort.Ints(accepted)
+		}
+	}
+
+	return index
+}
+
+// Lookup returns the earliest time step after lastT that code is accepted for.
+func (ci *CodeIndex) Lookup(code int, lastT int) (bool, int) {
+	return ci.lookup(code, func(t int) bool { return t <= lastT })
+}
+
+// lookup returns the earliest time step code is accepted for that isn't used.
+func (ci *CodeIndex) lookup(code int, used func(t int) bool) (bool, int) {
+	for _, t := range ci.codes[code] {
+		if !used(t) {
+			return true, t
+		}
+	}
+
+	return false, ci.T
+}
+
+// ValidateCached returns a bool indicating if code is valid for the provided time.
+// Codes for the current time step are computed once and cached until the step rolls
+// over, making repeated validations of the same key cheap. LastT and ReplayStore are
+// honored on every call and an accepted step is consumed as for ValidateStringCT. The cache is also rebuilt when the rotation grace period for
+// PreviousKey ends, but not when the validator's configuration changes.
+func (tc *TOTPValidator) ValidateCached(now time.Time, code int) bool {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.index == nil || tc.index.T != tc.stepAt(tc.stepSize(), now) || tc.index.keys != len(tc.keys(now)) {
+		tc.index = tc.NewCodeIndex(now)
+	}
+
+	ok, t := tc.index.lookup(code, tc.used)
+	if ok {
+		tc.recordUse(now, t)
+	}
+	return ok
+}
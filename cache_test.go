@@ -0,0 +1,93 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestValidateCached(t *testing.T) {
+	validator := &TOTPValidator{
+		Key:             []byte("12345678901234567890"),
+		HashProvider:    sha1.New,
+		Digits:          EightDigits,
+		PastTolerance:   30 * time.Second,
+		FutureTolerance: 30 * time.Second,
+	}
+
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name  string
+		Time  time.Time
+		Code  int
+		Match bool
+	}{
+		{"T-1", testTime, 89731029, true},
+		{"T", testTime, 7081804, true},
+		{"T+1", testTime, 14050471, true},
+		{"T+2", testTime, 44266759, false},
+		{"T+2 After Rollover", testTime.Add(30 * time.Second), 44266759, true},
+		{"T-1 After Rollover", testTime.Add(30 * time.Second), 89731029, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			match := validator.ValidateCached(test.Time, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+		})
+	}
+}
+
+func TestValidateCachedLastT(t *testing.T) {
+	validator := &TOTPValidator{
+		Key:             []byte("12345678901234567890"),
+		HashProvider:    sha1.New,
+		Digits:          EightDigits,
+		PastTolerance:   30 * time.Second,
+		FutureTolerance: 30 * time.Second,
+	}
+
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	if !validator.ValidateCached(testTime, 7081804) {
+		t.Fatal("Expected code to be valid")
+	}
+
+	validator.LastT = 0x23523EC
+	if validator.ValidateCached(testTime, 7081804) {
+		t.Error("Expected code to be rejected after LastT advanced")
+	}
+	if !validator.ValidateCached(testTime, 14050471) {
+		t.Error("Expected code after LastT to be valid")
+	}
+}
+
+func TestCodeIndexLookup(t *testing.T) {
+	validator := &TOTPValidator{
+		Key:          []byte("12345678901234567890"),
+		HashProvider: sha1.New,
+		Digits:       EightDigits,
+	}
+
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	index := validator.NewCodeIndex(testTime)
+
+	ok, tMatch := index.Lookup(7081804, 0)
+	if !ok {
+		t.Error("Code did not match")
+	}
+	if tMatch != 0x23523EC {
+		t.Errorf("T did not match. Expected %d and got %d.\n", 0x23523EC, tMatch)
+	}
+
+	ok, tMatch = index.Lookup(14050471, 0)
+	if ok {
+		t.Error("Code outside window matched")
+	}
+	if tMatch != index.T {
+		t.Errorf("T did not match. Expected %d and got %d.\n", index.T, tMatch)
+	}
+}
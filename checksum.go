@@ -0,0 +1,57 @@
+package otp
+
+// luhnDoubled maps a digit to the sum of the digits of twice its value.
+var luhnDoubled = [10]int{0, 2, 4, 6, 8, 1, 3, 5, 7, 9}
+
+// AddChecksum appends the Luhn mod 10 check digit described in RFC 4226 section 5.4 to a
+// code of the given digits, returning a code one digit longer. The check digit is
+// computed over exactly digits.Length() digits, including any leading zeros, as the
+// reference implementation does. false is returned for a negative code or one with more
+// than digits digits, and on 32 bit platforms for NineDigits and TenDigits codes whose
+// check digit makes them too large for an int.
+func AddChecksum(code int, digits Digits) (int, bool) {
+	if code < 0 || uint64(code) >= uint64(digits) {
+		return 0, false
+	}
+
+	withChecksum := uint64(code)*10 + uint64(luhnChecksum(code, digits.Length()))
+	if withChecksum > uint64(maxInt) {
+		return 0, false
+	}
+	return int(withChecksum), true
+}
+
+// VerifyChecksum strips the check digit appended by AddChecksum from codeWithChecksum and
+// returns the code along with whether the check digit is valid. digits is the number of
+// digits of the code without its check digit.
+func VerifyChecksum(codeWithChecksum int, digits Digits) (int, bool) {
+	if codeWithChecksum < 0 {
+		return 0, false
+	}
+
+	code := codeWithChecksum / 10
+	if uint64(code) >= uint64(digits) {
+		return code, false
+	}
+	return code, codeWithChecksum%10 == luhnChecksum(code, digits.Length())
+}
+
+// luhnChecksum returns the Luhn check digit for the last length decimal digits of code.
+func luhnChecksum(code int, length int) int {
+	total := 0
+	double := true
+	for i := 0; i < length; i++ {
+		digit := code % 10
+		code /= 10
+		if double {
+			digit = luhnDoubled[digit]
+		}
+		total += digit
+		double = !double
+	}
+
+	if check := total % 10; check > 0 {
+		return 10 - check
+	}
+	return 0
+}
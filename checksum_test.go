@@ -0,0 +1,91 @@
+package otp
+
+import "testing"
+
+func TestAddChecksum(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Code     int
+		Digits   Digits
+		Expected int
+		OK       bool
+	}{
+		{"Six Digits", 755224, SixDigits, 7552243, true},
+		{"Leading Zero", 81804, SixDigits, 818047, true},
+		{"Eight Digits", 7081804, EightDigits, 70818042, true},
+		{"Eight Digits Large", 79927398, EightDigits, 799273982, true},
+		{"Zero", 0, SixDigits, 0, true},
+		{"Negative", -755224, SixDigits, 0, false},
+		{"Too Long", 1755224, SixDigits, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code, ok := AddChecksum(test.Code, test.Digits)
+			if code != test.Expected {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", test.Expected, code)
+			}
+			if ok != test.OK {
+				t.Errorf("OK did not match. Expected %t and got %t.\n", test.OK, ok)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	tests := []struct {
+		Name             string
+		CodeWithChecksum int
+		Digits           Digits
+		Code             int
+		Valid            bool
+	}{
+		{"Valid", 7552243, SixDigits, 755224, true},
+		{"Leading Zero", 818047, SixDigits, 81804, true},
+		{"Wrong Check Digit", 7552244, SixDigits, 755224, false},
+		{"Transposed Digits", 7552423, SixDigits, 755242, false},
+		{"Too Long", 17552243, SixDigits, 1755224, false},
+		{"Negative", -7552243, SixDigits, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code, valid := VerifyChecksum(test.CodeWithChecksum, test.Digits)
+			if code != test.Code {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", test.Code, code)
+			}
+			if valid != test.Valid {
+				t.Errorf("Valid did not match. Expected %t and got %t.\n", test.Valid, valid)
+			}
+		})
+	}
+}
+
+func TestChecksumRoundTrip(t *testing.T) {
+	for i, vector := range rfc4226Vectors {
+		withChecksum, ok := AddChecksum(vector, SixDigits)
+		if !ok {
+			t.Errorf("AddChecksum rejected %d.\n", vector)
+		}
+		code, valid := VerifyChecksum(withChecksum, SixDigits)
+		if !valid || code != vector {
+			t.Errorf("Round trip did not match for %d. Expected %d and got %d (valid %t).\n", i, vector, code, valid)
+		}
+	}
+}
+
+func TestAddChecksumOverflow(t *testing.T) {
+	code := 999999999
+
+	// with its check digit the code only fits a 64 bit int
+	withChecksum, ok := AddChecksum(code, NineDigits)
+	if fits := uint64(maxInt) >= 9999999999; ok != fits {
+		t.Errorf("OK did not match. Expected %t and got %t.\n", fits, ok)
+	}
+	if ok && withChecksum/10 != code {
+		t.Errorf("Code did not match. Expected %d and got %d.\n", code, withChecksum/10)
+	}
+	if !ok && withChecksum != 0 {
+		t.Errorf("Code did not match. Expected 0 and got %d.\n", withChecksum)
+	}
+}
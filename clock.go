@@ -0,0 +1,65 @@
+package otp
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time to a TOTPValidator.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock using time.Now.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock is a Clock returning a fixed time for use in tests. The time only changes
+// when Set or Advance is called. It is safe for concurrent use.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set sets the clock's current time to now.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock's current time forward by d and returns the new time.
+func (c *FakeClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
+
+// Validate is ValidateTOTPCode at the current time of the validator's Clock.
+func (tc *TOTPValidator) Validate(code int) (bool, int) {
+	return tc.ValidateTOTPCode(tc.clock().Now(), code)
+}
+
+func (tc *TOTPValidator) clock() Clock {
+	if tc.Clock == nil {
+		return SystemClock{}
+	}
+	return tc.Clock
+}
@@ -0,0 +1,77 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	if now := clock.Now(); !now.Equal(start) {
+		t.Errorf("Now did not match. Expected %s and got %s.\n", start, now)
+	}
+
+	if now := clock.Advance(time.Second); !now.Equal(start.Add(time.Second)) {
+		t.Errorf("Advance did not match. Expected %s and got %s.\n", start.Add(time.Second), now)
+	}
+	if now := clock.Now(); !now.Equal(start.Add(time.Second)) {
+		t.Errorf("Now after Advance did not match. Expected %s and got %s.\n", start.Add(time.Second), now)
+	}
+
+	clock.Set(start)
+	if now := clock.Now(); !now.Equal(start) {
+		t.Errorf("Now after Set did not match. Expected %s and got %s.\n", start, now)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	clock := NewFakeClock(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC))
+	validator := &TOTPValidator{
+		Key:    []byte("12345678901234567890"),
+		Digits: EightDigits,
+		Clock:  clock,
+	}
+
+	tests := []struct {
+		Name    string
+		Advance time.Duration
+		Code    int
+		Match   bool
+		T       int
+	}{
+		{"Current Step", 0, 7081804, true, 0x23523EC},
+		{"Next Step", time.Second, 14050471, true, 0x23523ED},
+		{"Previous Code", 0, 7081804, false, 0x23523ED},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			clock.Advance(test.Advance)
+
+			match, tMatch := validator.Validate(test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+
+	// like ValidateTOTPCode, Validate only reads the validator
+	if lastUsed := validator.LastUsed(); !lastUsed.IsZero() {
+		t.Errorf("Validate updated LastUsed to %s", lastUsed)
+	}
+}
+
+func TestValidateSystemClock(t *testing.T) {
+	key := []byte("12345678901234567890")
+	validator := &TOTPValidator{Key: key, PastTolerance: time.Minute}
+
+	if ok, _ := validator.Validate(TOTPCode(sha1.New, key, SixDigits, 30, time.Now())); !ok {
+		t.Error("Code for the current time did not match")
+	}
+}
@@ -0,0 +1,43 @@
+package otp
+
+// Clone returns a copy of the validator, for example a per-request copy of a shared
+// template whose LastT or Clock can then be set without affecting the template.
+// Key, PreviousKey, CounterKey and AcceptOffsets are copied so wiping or modifying one
+// validator doesn't affect the other. HashProvider, Clock and ReplayStore are copied by
+// reference so a shared ReplayStore continues to reject replays across copies. The copy
+// has its own lock and an empty code cache.
+func (tc *TOTPValidator) Clone() *TOTPValidator {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	return &TOTPValidator{
+		Key:             append([]byte(nil), tc.Key...),
+		StepSizeSeconds: tc.StepSizeSeconds,
+		StepSize:        tc.StepSize,
+		Epoch:           tc.Epoch,
+		PastTolerance:   tc.PastTolerance,
+		FutureTolerance: tc.FutureTolerance,
+		PastSteps:       tc.PastSteps,
+		FutureSteps:     tc.FutureSteps,
+		MaxWindowSteps:  tc.MaxWindowSteps,
+		LastT:           tc.LastT,
+		ReplayStore:     tc.ReplayStore,
+		AcceptOffsets:   append([]int(nil), tc.AcceptOffsets...),
+		HashProvider:    tc.HashProvider,
+		Digits:          tc.Digits,
+		CounterKey:      append([]byte(nil), tc.CounterKey...),
+		FixedScanSteps:  tc.FixedScanSteps,
+
+		EnrolledAt:          tc.EnrolledAt,
+		EnrollmentAge:       tc.EnrollmentAge,
+		EnrollmentTolerance: tc.EnrollmentTolerance,
+
+		PreviousKey:   append([]byte(nil), tc.PreviousKey...),
+		RotatedAt:     tc.RotatedAt,
+		RotationGrace: tc.RotationGrace,
+
+		LastSuccess: tc.LastSuccess,
+
+		Clock: tc.Clock,
+	}
+}
@@ -0,0 +1,95 @@
+package otp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	validator := &TOTPValidator{
+		Key:                 []byte("12345678901234567890"),
+		StepSizeSeconds:     60,
+		StepSize:            time.Minute,
+		Epoch:               time.Unix(30, 0),
+		PastTolerance:       time.Minute,
+		FutureTolerance:     30 * time.Second,
+		PastSteps:           2,
+		FutureSteps:         1,
+		MaxWindowSteps:      5,
+		LastT:               0x23523EC,
+		ReplayStore:         NewReplayCache(10),
+		AcceptOffsets:       []int{-1, 0},
+		HashProvider:        sha256.New,
+		Digits:              EightDigits,
+		CounterKey:          []byte("counter key"),
+		FixedScanSteps:      4,
+		EnrolledAt:          now,
+		EnrollmentAge:       time.Hour,
+		EnrollmentTolerance: 5 * time.Minute,
+		PreviousKey:         []byte("previous key"),
+		RotatedAt:           now.Add(time.Minute),
+		RotationGrace:       time.Hour,
+		LastSuccess:         now.Add(time.Second),
+		Clock:               NewFakeClock(now),
+	}
+
+	clone := validator.Clone()
+
+	original, copied := reflect.ValueOf(validator).Elem(), reflect.ValueOf(clone).Elem()
+	for i := 0; i < original.NumField(); i++ {
+		field := original.Type().Field(i)
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		a, b := original.Field(i), copied.Field(i)
+		if a.IsZero() {
+			t.Errorf("Field %s is not set by the test.\n", field.Name)
+		}
+
+		equal := false
+		switch a.Kind() {
+		case reflect.Func:
+			equal = a.Pointer() == b.Pointer()
+		default:
+			equal = reflect.DeepEqual(a.Interface(), b.Interface())
+		}
+		if !equal {
+			t.Errorf("Field %s did not match. Expected %v and got %v.\n", field.Name, a, b)
+		}
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	key := []byte("12345678901234567890")
+	validator := &TOTPValidator{
+		Key:           key,
+		AcceptOffsets: []int{-1, 0},
+		ReplayStore:   NewReplayCache(10),
+	}
+
+	clone := validator.Clone()
+	clone.LastT = 0x23523EC
+	clone.AcceptOffsets[0] = 1
+	clone.Wipe()
+
+	if !bytes.Equal(key, []byte("12345678901234567890")) {
+		t.Errorf("Original key was wiped: %v\n", key)
+	}
+	if validator.LastT != 0 {
+		t.Errorf("Original LastT did not match. Expected 0 and got %d.\n", validator.LastT)
+	}
+	if validator.AcceptOffsets[0] != -1 {
+		t.Errorf("Original AcceptOffsets did not match. Expected -1 and got %d.\n", validator.AcceptOffsets[0])
+	}
+
+	clone.ReplayStore.Mark(5)
+	if !validator.ReplayStore.Seen(5) {
+		t.Error("ReplayStore was not shared")
+	}
+}
@@ -0,0 +1,139 @@
+package otp
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrEmptyCode is returned by ParseCode when no digits were provided.
+var ErrEmptyCode = errors.New("otp: code is empty")
+
+// ParseCode parses a code entered by a user. Surrounding whitespace is trimmed and spaces
+// and dashes within the code, as in "081 804" or "0818-04", are ignored. Any other
+// non-digit character is an error.
+func ParseCode(s string) (int, error) {
+	digits, err := codeDigits(s)
+	if err != nil {
+		return 0, err
+	}
+	return parseCodeDigits(digits)
+}
+
+// codeDigits returns the digits of a code entered by a user as described by ParseCode.
+func codeDigits(s string) (string, error) {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '-' || unicode.IsSpace(r):
+		default:
+			return "", fmt.Errorf("otp: invalid character %q in code", r)
+		}
+	}
+
+	if b.Len() == 0 {
+		return "", ErrEmptyCode
+	}
+	return b.String(), nil
+}
+
+func parseCodeDigits(digits string) (int, error) {
+	code, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, fmt.Errorf("otp: invalid code: %w", err)
+	}
+	return code, nil
+}
+
+// ValidateTOTPString parses code like ParseCode then validates it with ValidateTOTPCode.
+// The code must have exactly as many digits as the validator's Digits, so a short code
+// such as "81804" is rejected rather than matching "081804". A code that can't be parsed
+// or has the wrong length is rejected without computing any codes.
+func (tc *TOTPValidator) ValidateTOTPString(now time.Time, code string) (bool, int) {
+	digits, err := codeDigits(code)
+	if err != nil || len(digits) != tc.digits().Length() {
+		return false, tc.stepAt(tc.stepSize(), now)
+	}
+
+	c, err := parseCodeDigits(digits)
+	if err != nil {
+		return false, tc.stepAt(tc.stepSize(), now)
+	}
+
+	return tc.ValidateTOTPCode(now, c)
+}
+
+// ValidateStringCT returns a bool indicating if code is valid for the provided time.
+// Unlike ValidateTOTPCode the code is compared as a fixed width string so "081804" and
+// "81804" are not equivalent. Each step in the window is compared in constant time and
+// the whole window is always scanned, so timing does not reveal how close a guess was or
+// where it matched. Surrounding whitespace in code is ignored.
+// The returned T has the same meaning as for ValidateTOTPCode. An accepted step is
+// consumed, by marking it with the ReplayStore or else advancing LastT, and recorded
+// with LastSuccess.
+func (tc *TOTPValidator) ValidateStringCT(now time.Time, code string) (bool, int) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	ok, t := tc.validateStringCT(now, code)
+	if ok {
+		tc.recordUse(now, t)
+	}
+	return ok, t
+}
+
+// validateStringCT is ValidateStringCT without recording success, for callers holding
+// tc.mu.
+func (tc *TOTPValidator) validateStringCT(now time.Time, code string) (bool, int) {
+	digits := tc.digits()
+	steps := tc.steps(now)
+	input := []byte(strings.TrimSpace(code))
+
+	matched := 0
+	tMatch := tc.stepAt(tc.stepSize(), now)
+	for _, g := range tc.generators(now) {
+		for _, t := range steps {
+			if tc.used(t) {
+				continue
+			}
+
+			candidate := []byte(FormatCode(g.code(int64(t)), digits, FormatOptions{Pad: true}))
+			isMatch := subtle.ConstantTimeCompare(candidate, input)
+			tMatch = subtle.ConstantTimeSelect(isMatch&^matched, t, tMatch)
+			matched |= isMatch
+		}
+	}
+
+	return matched == 1, tMatch
+}
+
+// ValidatePinOTP validates input formed by a static pin followed by a TOTP code, a common
+// pattern for RADIUS and VPN logins. pin is the expected pin which is compared against
+// the start of combined in constant time. The remainder of combined is validated with
+// ValidateStringCT. Both checks are always performed so a failure does not reveal which
+// part was wrong. The returned T has the same meaning as for ValidateTOTPCode. An
+// accepted step is consumed as for ValidateStringCT and recorded with LastSuccess.
+func (tc *TOTPValidator) ValidatePinOTP(now time.Time, pin string, combined string) (bool, int) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if len(combined) < len(pin) {
+		_, t := tc.validateStringCT(now, "")
+		return false, t
+	}
+
+	pinOK := subtle.ConstantTimeCompare([]byte(combined[:len(pin)]), []byte(pin)) == 1
+	codeOK, t := tc.validateStringCT(now, combined[len(pin):])
+
+	if pinOK && codeOK {
+		tc.recordUse(now, t)
+		return true, t
+	}
+	return false, t
+}
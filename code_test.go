@@ -0,0 +1,179 @@
+package otp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input string
+		Code  int
+		Err   string
+	}{
+		{"Plain", "081804", 81804, ""},
+		{"No Leading Zero", "81804", 81804, ""},
+		{"Zero", "000000", 0, ""},
+		{"Surrounding Whitespace", " \t081804\n", 81804, ""},
+		{"Interior Space", "081 804", 81804, ""},
+		{"Interior Dash", "0818-04", 81804, ""},
+		{"Mixed Separators", "08 18-04", 81804, ""},
+		{"Empty", "", 0, "otp: code is empty"},
+		{"Whitespace Only", "  ", 0, "otp: code is empty"},
+		{"Separators Only", " - ", 0, "otp: code is empty"},
+		{"Letter", "08180a", 0, `otp: invalid character 'a' in code`},
+		{"Sign", "-081804x", 0, `otp: invalid character 'x' in code`},
+		{"Plus", "+081804", 0, `otp: invalid character '+' in code`},
+		{"Too Long", "123456789012345678901234567890", 0, `otp: invalid code: strconv.Atoi: parsing "123456789012345678901234567890": value out of range`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code, err := ParseCode(test.Input)
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if code != test.Code {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", test.Code, code)
+			}
+		})
+	}
+
+	if _, err := ParseCode(""); !errors.Is(err, ErrEmptyCode) {
+		t.Errorf("Expected ErrEmptyCode but got %v", err)
+	}
+}
+
+func TestValidateTOTPString(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Code  string
+		Match bool
+		T     int
+	}{
+		{"T Match", "081804", true, 0x23523EC},
+		{"T Match Formatted", " 081 804 ", true, 0x23523EC},
+		{"T-1 Match", "731-029", true, 0x23523EB},
+		{"No Match", "081803", false, 0x23523EC},
+		{"Invalid", "08180a", false, 0x23523EC},
+		{"Empty", "", false, 0x23523EC},
+		{"Missing Leading Zero", "81804", false, 0x23523EC},
+		{"Extra Leading Zero", "0081804", false, 0x23523EC},
+		{"Too Long", "0818041", false, 0x23523EC},
+		{"Sign", "+81804", false, 0x23523EC},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:           []byte("12345678901234567890"),
+				PastTolerance: 30 * time.Second,
+			}
+
+			testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+			match, tMatch := validator.ValidateTOTPString(testTime, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
+
+func TestValidateStringCT(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Code   string
+		Digits Digits
+		Match  bool
+		T      int
+		LastT  int
+	}{
+		{"T Match", "07081804", EightDigits, true, 0x23523EC, 0},
+		{"T Match Whitespace", " 07081804\n", EightDigits, true, 0x23523EC, 0},
+		{"T Match Missing Leading Zero", "7081804", EightDigits, false, 0x23523EC, 0},
+		{"T Match Six Digits", "081804", SixDigits, true, 0x23523EC, 0},
+		{"T Match Six Digits Missing Leading Zero", "81804", SixDigits, false, 0x23523EC, 0},
+		{"T-1 Match", "89731029", EightDigits, true, 0x23523EB, 0},
+		{"T+1 Match", "14050471", EightDigits, true, 0x23523ED, 0},
+		{"T+2 Match", "44266759", EightDigits, false, 0x23523EC, 0},
+		{"No Match", "07081803", EightDigits, false, 0x23523EC, 0},
+		{"Empty", "", EightDigits, false, 0x23523EC, 0},
+		{"Not Digits", "0708180a", EightDigits, false, 0x23523EC, 0},
+		{"T Match LastT", "07081804", EightDigits, false, 0x23523EC, 0x23523EC},
+		{"T+1 Match LastT", "14050471", EightDigits, true, 0x23523ED, 0x23523EC},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          test.Digits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+				LastT:           test.LastT,
+			}
+
+			testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+			match, tMatch := validator.ValidateStringCT(testTime, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
+
+func TestValidatePinOTP(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Pin      string
+		Combined string
+		Match    bool
+		T        int
+	}{
+		{"Match", "1234", "1234081804", true, 0x23523EC},
+		{"Match Previous Step", "1234", "1234731029", true, 0x23523EB},
+		{"Match Alphanumeric Pin", "s3cr3t", "s3cr3t081804", true, 0x23523EC},
+		{"Match Empty Pin", "", "081804", true, 0x23523EC},
+		{"Wrong Pin", "1234", "1235081804", false, 0x23523EC},
+		{"Wrong Code", "1234", "1234081803", false, 0x23523EC},
+		{"Missing Pin", "1234", "081804", false, 0x23523EC},
+		{"Missing Code", "1234", "1234", false, 0x23523EC},
+		{"Short Input", "1234", "12", false, 0x23523EC},
+		{"Pin After Code", "1234", "0818041234", false, 0x23523EC},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:           []byte("12345678901234567890"),
+				PastTolerance: 30 * time.Second,
+			}
+
+			testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+			match, tMatch := validator.ValidatePinOTP(testTime, test.Pin, test.Combined)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
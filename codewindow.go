@@ -0,0 +1,36 @@
+package otp
+
+import (
+	"hash"
+	"time"
+)
+
+// TOTPCodeWindow generates the TOTP codes for the time steps from before steps before to
+// after steps after the one containing t, in ascending order. The result has
+// before+after+1 codes with the code for t at index before. nil is returned if before or
+// after is negative or stepSizeSeconds isn't positive.
+func TOTPCodeWindow(hashProvider func() hash.Hash, key []byte, digits Digits, stepSizeSeconds int, t time.Time, before, after int) []int {
+	if before < 0 || after < 0 || stepSizeSeconds <= 0 {
+		return nil
+	}
+
+	g := mustHOTPGenerator(hashProvider, key, digits)
+	current := int64(timeSteps(stepSizeSeconds, t))
+	codes := make([]int, 0, before+after+1)
+	for step := current - int64(before); step <= current+int64(after); step++ {
+		codes = append(codes, g.code(step))
+	}
+
+	return codes
+}
+
+// TOTPCurrentAndNext returns the TOTP code for t and the code for the following time
+// step, as shown by authenticator apps that preview the next code. Both codes are 0 if
+// stepSizeSeconds isn't positive.
+func TOTPCurrentAndNext(hashProvider func() hash.Hash, key []byte, digits Digits, stepSizeSeconds int, t time.Time) (int, int) {
+	codes := TOTPCodeWindow(hashProvider, key, digits, stepSizeSeconds, t, 0, 1)
+	if len(codes) == 0 {
+		return 0, 0
+	}
+	return codes[0], codes[1]
+}
@@ -0,0 +1,67 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"hash"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTOTPCodeWindow(t *testing.T) {
+	key := []byte("12345678901234567890")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name            string
+		StepSizeSeconds int
+		Before          int
+		After           int
+		Codes           []int
+	}{
+		{"Current", DefaultStepSizeSeconds, 0, 0, []int{7081804}},
+		{"Previous And Next", DefaultStepSizeSeconds, 1, 1, []int{89731029, 7081804, 14050471}},
+		{"Next Two", DefaultStepSizeSeconds, 0, 2, []int{7081804, 14050471, 44266759}},
+		{"Negative Before", DefaultStepSizeSeconds, -1, 1, nil},
+		{"Negative After", DefaultStepSizeSeconds, 1, -1, nil},
+		{"Zero Step Size", 0, 1, 1, nil},
+		{"Negative Step Size", -30, 1, 1, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			codes := TOTPCodeWindow(sha1.New, key, EightDigits, test.StepSizeSeconds, testTime, test.Before, test.After)
+			if !reflect.DeepEqual(codes, test.Codes) {
+				t.Errorf("Codes did not match. Expected %v and got %v.\n", test.Codes, codes)
+			}
+		})
+	}
+}
+
+func TestTOTPCurrentAndNext(t *testing.T) {
+	key := []byte("12345678901234567890")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	current, next := TOTPCurrentAndNext(sha1.New, key, EightDigits, DefaultStepSizeSeconds, testTime)
+	if current != 7081804 {
+		t.Errorf("Current code did not match. Expected %d and got %d.\n", 7081804, current)
+	}
+	if next != 14050471 {
+		t.Errorf("Next code did not match. Expected %d and got %d.\n", 14050471, next)
+	}
+}
+
+func TestTOTPCodeWindowReusesHMAC(t *testing.T) {
+	hashes := 0
+	hashProvider := func() hash.Hash {
+		hashes++
+		return sha1.New()
+	}
+
+	TOTPCodeWindow(hashProvider, []byte("12345678901234567890"), EightDigits, DefaultStepSizeSeconds, time.Now(), 2, 2)
+
+	// a single HMAC creates an inner and outer hash for all 5 codes
+	if hashes != 2 {
+		t.Errorf("Hashes did not match. Expected %d and got %d.\n", 2, hashes)
+	}
+}
@@ -0,0 +1,56 @@
+package otp
+
+import (
+	"hash"
+	"time"
+)
+
+// Config holds the parameters shared by generating and validating TOTP codes for a key so
+// that codes displayed at enrollment and validated by a server can't disagree. Zero values
+// for HashProvider, Digits and StepSizeSeconds default to DefaultHashProvider,
+// DefaultDigits and DefaultStepSizeSeconds as they do for TOTPValidator.
+type Config struct {
+	Key             []byte
+	HashProvider    func() hash.Hash
+	Digits          Digits
+	StepSizeSeconds int
+}
+
+// Code returns the TOTP code for t as TOTPCode does.
+func (c Config) Code(t time.Time) int {
+	return TOTPCode(c.hashProvider(), c.Key, c.digits(), c.stepSizeSeconds(), t)
+}
+
+// Validator returns a validator for codes generated with c, accepting codes from past
+// before to future after the validation time. The validator shares c's Key.
+func (c Config) Validator(past, future time.Duration) *TOTPValidator {
+	return &TOTPValidator{
+		Key:             c.Key,
+		HashProvider:    c.HashProvider,
+		Digits:          c.Digits,
+		StepSizeSeconds: c.StepSizeSeconds,
+		PastTolerance:   past,
+		FutureTolerance: future,
+	}
+}
+
+func (c Config) hashProvider() func() hash.Hash {
+	if c.HashProvider == nil {
+		return DefaultHashProvider
+	}
+	return c.HashProvider
+}
+
+func (c Config) digits() Digits {
+	if c.Digits == 0 {
+		return DefaultDigits
+	}
+	return c.Digits
+}
+
+func (c Config) stepSizeSeconds() int {
+	if c.StepSizeSeconds == 0 {
+		return DefaultStepSizeSeconds
+	}
+	return c.StepSizeSeconds
+}
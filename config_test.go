@@ -0,0 +1,53 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"testing"
+	"time"
+)
+
+func TestConfigCode(t *testing.T) {
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name   string
+		Config Config
+		Code   int
+	}{
+		{"Defaults", Config{Key: []byte("12345678901234567890")}, 81804},
+		{"Explicit", Config{Key: []byte("12345678901234567890"), HashProvider: sha1.New, Digits: EightDigits, StepSizeSeconds: 30}, 7081804},
+		{"SHA256", Config{Key: []byte("12345678901234567890123456789012"), HashProvider: sha256.New, Digits: EightDigits}, 68084774},
+		{"Minute Step", Config{Key: []byte("12345678901234567890"), StepSizeSeconds: 60},
+			TOTPCode(sha1.New, []byte("12345678901234567890"), SixDigits, 60, testTime)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if code := test.Config.Code(testTime); code != test.Code {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", test.Code, code)
+			}
+		})
+	}
+}
+
+func TestConfigValidator(t *testing.T) {
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	for _, config := range []Config{
+		{Key: []byte("12345678901234567890")},
+		{Key: []byte("12345678901234567890123456789012"), HashProvider: sha256.New, Digits: EightDigits, StepSizeSeconds: 60},
+	} {
+		validator := config.Validator(time.Minute, 0)
+
+		if ok, _ := validator.ValidateTOTPCode(testTime, config.Code(testTime)); !ok {
+			t.Errorf("Current code did not match for %+v.\n", config)
+		}
+		if ok, _ := validator.ValidateTOTPCode(testTime.Add(time.Minute), config.Code(testTime)); !ok {
+			t.Errorf("Past code did not match for %+v.\n", config)
+		}
+		if ok, _ := validator.ValidateTOTPCode(testTime.Add(-2*time.Minute), config.Code(testTime)); ok {
+			t.Errorf("Future code matched for %+v.\n", config)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package otp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// ConfigKey returns a stable string identifying the validator's algorithm, digits,
+// step size, Epoch and key. It is suitable for keying caches of validators or precomputed
+// codes. The key is represented by a SHA-256 fingerprint so the secret itself is not
+// exposed. Defaults are applied before deriving the string so a zero value field and
+// its explicit default produce the same ConfigKey, including a zero Epoch and the Unix
+// epoch.
+func (tc *TOTPValidator) ConfigKey() string {
+	// identify the algorithm by its digest of an empty input
+	algorithm := tc.hashProvider()().Sum(nil)
+
+	fingerprint := sha256.New()
+	fingerprint.Write([]byte("otp.ConfigKey"))
+	fingerprint.Write(tc.Key)
+
+	return fmt.Sprintf("%s:%d:%s:%d:%s",
+		hex.EncodeToString(algorithm[:8]),
+		tc.digits(),
+		tc.stepSize(),
+		int64(epochOffset(tc.Epoch)),
+		hex.EncodeToString(fingerprint.Sum(nil)))
+}
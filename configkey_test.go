@@ -0,0 +1,52 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigKey(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	base := &TOTPValidator{Key: key}
+
+	tests := []struct {
+		Name      string
+		Validator *TOTPValidator
+		Equal     bool
+	}{
+		{"Identical", &TOTPValidator{Key: []byte("12345678901234567890")}, true},
+		{"Explicit Defaults", &TOTPValidator{Key: key, HashProvider: sha1.New, Digits: SixDigits, StepSizeSeconds: DefaultStepSizeSeconds}, true},
+		{"Step Duration", &TOTPValidator{Key: key, StepSize: DefaultStepSizeSeconds * time.Second}, true},
+		{"Tolerance Ignored", &TOTPValidator{Key: key, PastTolerance: time.Minute, LastT: 5}, true},
+		{"Different Key", &TOTPValidator{Key: []byte("12345678901234567891")}, false},
+		{"Different Hash", &TOTPValidator{Key: key, HashProvider: sha256.New}, false},
+		{"Different Digits", &TOTPValidator{Key: key, Digits: EightDigits}, false},
+		{"Different Step", &TOTPValidator{Key: key, StepSizeSeconds: 60}, false},
+		{"Different Step Duration", &TOTPValidator{Key: key, StepSize: time.Millisecond}, false},
+		{"Unix Epoch", &TOTPValidator{Key: key, Epoch: time.Unix(0, 0)}, true},
+		{"Different Epoch", &TOTPValidator{Key: key, Epoch: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}, false},
+		{"Epoch Within Step", &TOTPValidator{Key: key, Epoch: time.Unix(1, 0)}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			equal := base.ConfigKey() == test.Validator.ConfigKey()
+			if equal != test.Equal {
+				t.Errorf("Equality did not match. Expected %t and got %t.\n", test.Equal, equal)
+			}
+		})
+	}
+}
+
+func TestConfigKeyOmitsSecret(t *testing.T) {
+	validator := &TOTPValidator{Key: []byte("12345678901234567890")}
+
+	configKey := validator.ConfigKey()
+	if strings.Contains(configKey, "12345678901234567890") || strings.Contains(configKey, "3132333435363738393031323334353637383930") {
+		t.Errorf("ConfigKey exposed the secret: %s", configKey)
+	}
+}
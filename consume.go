@@ -0,0 +1,26 @@
+package otp
+
+import (
+	"time"
+)
+
+// VerifyAndConsume returns whether code is valid for the provided time and, if it is,
+// advances LastT to the matched step so the code can't be used again. It mutates the
+// receiver. Concurrent calls are serialized so two callers can't both accept a code for
+// the same step, which is not the case when calling ValidateTOTPCode and assigning LastT.
+func (tc *TOTPValidator) VerifyAndConsume(now time.Time, code int) bool {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	ok, t := tc.validate(now, code)
+	if !ok {
+		return false
+	}
+
+	if t > tc.LastT {
+		tc.LastT = t
+	}
+	tc.mark(t)
+	tc.recordSuccessLocked(now)
+	return true
+}
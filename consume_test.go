@@ -0,0 +1,154 @@
+package otp
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVerifyAndConsume(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	validator := &TOTPValidator{
+		Key:             []byte("12345678901234567890"),
+		Digits:          EightDigits,
+		PastTolerance:   30 * time.Second,
+		FutureTolerance: 30 * time.Second,
+	}
+
+	tests := []struct {
+		Name  string
+		Code  int
+		Match bool
+		LastT int
+	}{
+		{"T", 7081804, true, 0x23523EC},
+		{"T Reused", 7081804, false, 0x23523EC},
+		{"T-1 Before LastT", 89731029, false, 0x23523EC},
+		{"Wrong Code", 7081803, false, 0x23523EC},
+		{"T+1", 14050471, true, 0x23523ED},
+		{"T+1 Reused", 14050471, false, 0x23523ED},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			match := validator.VerifyAndConsume(now, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if validator.LastT != test.LastT {
+				t.Errorf("LastT did not match. Expected %d and got %d.\n", test.LastT, validator.LastT)
+			}
+		})
+	}
+
+	if lastUsed := validator.LastUsed(); !lastUsed.Equal(now) {
+		t.Errorf("LastUsed did not match. Expected %s and got %s.\n", now, lastUsed)
+	}
+}
+
+func TestVerifyAndConsumeReplayStore(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	validator := &TOTPValidator{
+		Key:             []byte("12345678901234567890"),
+		Digits:          EightDigits,
+		PastTolerance:   30 * time.Second,
+		FutureTolerance: 30 * time.Second,
+		LastT:           0x23523EA,
+		ReplayStore:     NewReplayCache(3),
+	}
+
+	if !validator.VerifyAndConsume(now, 14050471) {
+		t.Fatal("T+1 did not match")
+	}
+	// LastT now blocks T-1 regardless of the ReplayStore
+	if validator.VerifyAndConsume(now, 89731029) {
+		t.Error("T-1 matched after LastT advanced")
+	}
+	if !validator.ReplayStore.Seen(0x23523ED) {
+		t.Error("T+1 was not marked")
+	}
+}
+
+func TestVerifyAndConsumeConcurrent(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	validator := &TOTPValidator{
+		Key:    []byte("12345678901234567890"),
+		Digits: EightDigits,
+	}
+
+	const callers = 20
+	results := make(chan bool, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			results <- validator.VerifyAndConsume(now, 7081804)
+		}()
+	}
+
+	accepted := 0
+	for i := 0; i < callers; i++ {
+		if <-results {
+			accepted++
+		}
+	}
+	if accepted != 1 {
+		t.Errorf("Accepted did not match. Expected 1 and got %d.\n", accepted)
+	}
+}
+
+// TestConcurrentValidation mixes each validation method with consumption of the same
+// code. Run with -race to check LastT, LastSuccess and the ReplayStore are accessed safely.
+func TestConcurrentValidation(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	validator := &TOTPValidator{
+		Key:             []byte("12345678901234567890"),
+		Digits:          EightDigits,
+		PastTolerance:   30 * time.Second,
+		FutureTolerance: 30 * time.Second,
+		ReplayStore:     NewReplayCache(3),
+	}
+
+	sigKey := []byte("signature key")
+	validations := []func(){
+		func() { validator.ValidateTOTPCode(now, 7081803) },
+		func() { validator.ValidateStringCT(now, "07081803") },
+		func() { validator.ValidatePinOTP(now, "1234", "123407081803") },
+		func() { validator.ValidateBetween(now, now.Add(time.Second), 7081803) },
+		func() { validator.ValidateDualPeriod(now, 7081803, []int{30, 60}) },
+		func() {
+			validator.ValidateSignedRequest(now, 7081803, now.Unix(), SignRequest(sigKey, 7081803, now.Unix()), sigKey)
+		},
+		func() { validator.MatchingSteps(now, 7081803) },
+		func() { validator.EstimatedCost(now) },
+		func() { validator.LastUsed() },
+	}
+
+	const callers = 50
+	var wg sync.WaitGroup
+	consumed := make(chan bool, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			consumed <- validator.VerifyAndConsume(now, 7081804)
+		}()
+		go func(validate func()) {
+			defer wg.Done()
+			validate()
+		}(validations[i%len(validations)])
+	}
+	wg.Wait()
+	close(consumed)
+
+	accepted := 0
+	for ok := range consumed {
+		if ok {
+			accepted++
+		}
+	}
+	if accepted != 1 {
+		t.Errorf("Accepted did not match. Expected 1 and got %d.\n", accepted)
+	}
+	if validator.LastT != 0x23523EC {
+		t.Errorf("LastT did not match. Expected %d and got %d.\n", 0x23523EC, validator.LastT)
+	}
+}
@@ -0,0 +1,29 @@
+package otp
+
+import (
+	"time"
+)
+
+// deviceBoundDomain prefixes the device ID in the associated data of device bound codes
+// so they can't collide with codes from TOTPCodeAAD for the same key.
+const deviceBoundDomain = "otp.DeviceBound\x00"
+
+// DeviceBoundTOTP generates a TOTP code bound to the fingerprint of a device, so a code
+// generated for one device is rejected for any other. The device ID is mixed into the
+// HMAC as associated data, as for TOTPCodeAAD, so standard authenticator apps can't
+// generate these codes and both sides must know the device ID. cfg.Key is ignored in
+// favour of key. DeviceBoundTOTP panics if HOTPCodeErr would return an error.
+func DeviceBoundTOTP(key []byte, deviceID []byte, cfg Config, t time.Time) int {
+	return TOTPCodeAAD(cfg, key, deviceBoundAAD(deviceID), t)
+}
+
+// ValidateDeviceBound returns a bool indicating if code is valid for the provided time
+// and device as generated by DeviceBoundTOTP. The window, T and constant time comparison
+// are as for ValidateTOTPCode, and like ValidateTOTPCode it only reads the validator.
+func (tc *TOTPValidator) ValidateDeviceBound(deviceID []byte, now time.Time, code int) (bool, int) {
+	return tc.ValidateAAD(now, deviceBoundAAD(deviceID), code)
+}
+
+func deviceBoundAAD(deviceID []byte) []byte {
+	return append([]byte(deviceBoundDomain), deviceID...)
+}
@@ -0,0 +1,44 @@
+package otp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeviceBoundTOTP(t *testing.T) {
+	key := []byte("12345678901234567890")
+	phone := []byte("device:phone")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	cfg := Config{Digits: EightDigits}
+
+	tests := []struct {
+		Name   string
+		Device []byte
+		Code   int
+		Match  bool
+	}{
+		{"Same Device", phone, DeviceBoundTOTP(key, phone, cfg, testTime), true},
+		{"Other Device", []byte("device:laptop"), DeviceBoundTOTP(key, phone, cfg, testTime), false},
+		{"Standard Code", phone, 7081804, false},
+		{"Transaction Code", phone, TOTPCodeAAD(cfg, key, phone, testTime), false},
+		{"No Device", nil, DeviceBoundTOTP(key, nil, cfg, testTime), true},
+		{"No Device Standard Code", nil, 7081804, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:    key,
+				Digits: EightDigits,
+			}
+
+			match, tMatch := validator.ValidateDeviceBound(test.Device, testTime, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != 0x23523EC {
+				t.Errorf("T did not match. Expected %d and got %d.\n", 0x23523EC, tMatch)
+			}
+		})
+	}
+}
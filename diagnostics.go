@@ -0,0 +1,158 @@
+package otp
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"text/tabwriter"
+	"time"
+)
+
+// WindowBitmap returns, for each time step the validator scans at now in ascending order,
+// whether code matches that step under any accepted key. LastT is not applied so matches
+// for already used steps remain visible. Every code is compared in constant time.
+func (tc *TOTPValidator) WindowBitmap(now time.Time, code int) []bool {
+	steps := tc.steps(now)
+	matched := make([]int, len(steps))
+	for _, g := range tc.generators(now) {
+		for i, t := range steps {
+			matched[i] |= codesEqual(g.code(int64(t)), code)
+		}
+	}
+
+	bitmap := make([]bool, len(steps))
+	for i := range matched {
+		bitmap[i] = matched[i] == 1
+	}
+	return bitmap
+}
+
+// MatchingSteps returns every time step the validator scans at now whose code matches
+// code, in ascending order. Normally at most one step matches but collisions are possible
+// in a wide window. Steps at or before LastT or seen by ReplayStore are skipped and every
+// other step is compared in constant time. The returned slice is empty, not nil, when
+// nothing matches.
+func (tc *TOTPValidator) MatchingSteps(now time.Time, code int) []int {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	generators := tc.generators(now)
+
+	matches := []int{}
+	for _, t := range tc.steps(now) {
+		if tc.used(t) {
+			continue
+		}
+
+		matched := 0
+		for _, g := range generators {
+			matched |= codesEqual(g.code(int64(t)), code)
+		}
+		if matched == 1 {
+			matches = append(matches, t)
+		}
+	}
+
+	return matches
+}
+
+// WindowBounds returns the first and last time steps the validator scans at now, after
+// applying the tolerances, any enrollment tolerance and the default step size. With
+// AcceptOffsets the steps between the bounds may not all be scanned. LastT is not
+// applied so the full considered range is returned. For an empty window, as a negative
+// tolerance gives, the last step is before the first.
+func (tc *TOTPValidator) WindowBounds(now time.Time) (int, int) {
+	steps := tc.steps(now)
+	if len(steps) == 0 {
+		return tc.window(tc.stepSize(), now)
+	}
+	return steps[0], steps[len(steps)-1]
+}
+
+// IsNearBoundary returns whether now is within threshold of the start or end of its
+// time step. Near a boundary a client whose clock differs slightly from now may be
+// showing a different code, so a UI can warn that the code is about to change.
+func (tc *TOTPValidator) IsNearBoundary(now time.Time, threshold time.Duration) bool {
+	stepSize := tc.stepSize()
+
+	// computed from durations rather than int time steps which overflow for sub-second
+	// step sizes on 32 bit platforms
+	elapsed := now.Add(-epochOffset(tc.Epoch)).Sub(unixEpoch) % stepSize
+	remaining := stepSize - elapsed
+
+	return elapsed <= threshold || remaining <= threshold
+}
+
+// commonStepSizes are tried by DetectStepSizeMismatch when no candidates are provided.
+var commonStepSizes = []int{30, 60, 15, 10, 20, 90, 120}
+
+// DetectStepSizeMismatch returns the first step size in candidateSteps for which code is
+// the valid TOTP code at now. When candidateSteps is empty a list of commonly used step
+// sizes is tried. It is a diagnostic for tokens which fail validation because the token
+// and server disagree on the step size.
+func DetectStepSizeMismatch(key []byte, algo func() hash.Hash, digits Digits, now time.Time, code int, candidateSteps []int) (int, bool) {
+	if len(candidateSteps) == 0 {
+		candidateSteps = commonStepSizes
+	}
+
+	for _, stepSizeSeconds := range candidateSteps {
+		if stepSizeSeconds <= 0 {
+			continue
+		}
+
+		if TOTPCode(algo, key, digits, stepSizeSeconds, now) == code {
+			return stepSizeSeconds, true
+		}
+	}
+
+	return 0, false
+}
+
+// diagnosticDigits are the digit configurations included in DiagnosticTable.
+var diagnosticDigits = []Digits{SixDigits, SevenDigits, EightDigits}
+
+// DiagnosticTable returns a text table of the TOTP code for key at t using the default
+// step size for each registered algorithm and standard number of digits. Comparing the
+// table against the code a user's device shows reveals algorithm and digit mismatches.
+func DiagnosticTable(key []byte, t time.Time) string {
+	var buf bytes.Buffer
+
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ALGORITHM\tDIGITS\tCODE")
+	for _, algorithm := range registeredHashes() {
+		for _, digits := range diagnosticDigits {
+			code := TOTPCodeString(algorithm.hashProvider, key, digits, DefaultStepSizeSeconds, t)
+			fmt.Fprintf(w, "%s\t%d\t%s\n", algorithm.name, digits.Length(), code)
+		}
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// EstimatedCost returns the number of HMAC computations a validation at now performs when
+// no code matches, which is the most it can perform. Steps at or before LastT or seen by
+// ReplayStore are skipped unless FixedScanSteps is set, in which case the cost is fixed.
+// Every step is computed for each key, so the cost doubles while PreviousKey is accepted.
+func (tc *TOTPValidator) EstimatedCost(now time.Time) int {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	steps := tc.steps(now)
+	keys := len(tc.keys(now))
+
+	if tc.FixedScanSteps > 0 {
+		if len(steps) > tc.FixedScanSteps {
+			return len(steps) * keys
+		}
+		return tc.FixedScanSteps * keys
+	}
+
+	cost := 0
+	for _, t := range steps {
+		if !tc.used(t) {
+			cost++
+		}
+	}
+	return cost * keys
+}
@@ -0,0 +1,261 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWindowBitmap(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Code            int
+		PastTolerance   int
+		FutureTolerance int
+		Offsets         []int
+		Bitmap          []bool
+	}{
+		{"T No Window", 7081804, 0, 0, nil, []bool{true}},
+		{"T-1 No Window", 89731029, 0, 0, nil, []bool{false}},
+		{"T-1 1 Window", 89731029, 30, 30, nil, []bool{true, false, false}},
+		{"T 1 Window", 7081804, 30, 30, nil, []bool{false, true, false}},
+		{"T+1 1 Window", 14050471, 30, 30, nil, []bool{false, false, true}},
+		{"T+2 2 Window", 44266759, 60, 60, nil, []bool{false, false, false, false, true}},
+		{"T+2 Offsets", 44266759, 0, 0, []int{2, -1}, []bool{false, true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastTolerance:   time.Duration(test.PastTolerance) * time.Second,
+				FutureTolerance: time.Duration(test.FutureTolerance) * time.Second,
+				AcceptOffsets:   test.Offsets,
+				LastT:           0x23523EE, // ignored
+			}
+
+			testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+			bitmap := validator.WindowBitmap(testTime, test.Code)
+			if !reflect.DeepEqual(bitmap, test.Bitmap) {
+				t.Errorf("Bitmap did not match. Expected %v and got %v.\n", test.Bitmap, bitmap)
+			}
+		})
+	}
+}
+
+func TestMatchingSteps(t *testing.T) {
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name      string
+		Digits    Digits
+		Code      int
+		Tolerance time.Duration
+		LastT     int
+		Steps     []int
+	}{
+		{"Single Match", EightDigits, 7081804, 30 * time.Second, 0, []int{0x23523EC}},
+		{"No Match", EightDigits, 12345678, 30 * time.Second, 0, []int{}},
+		// single digit codes collide often across a wide window
+		{"Collisions", Digits(10), 9, 120 * time.Second, 0, []int{0x23523EB, 0x23523EE}},
+		{"LastT", Digits(10), 4, 120 * time.Second, 0x23523EC, []int{0x23523F0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          test.Digits,
+				PastTolerance:   test.Tolerance,
+				FutureTolerance: test.Tolerance,
+				LastT:           test.LastT,
+			}
+
+			steps := validator.MatchingSteps(testTime, test.Code)
+			if !reflect.DeepEqual(steps, test.Steps) {
+				t.Errorf("Steps did not match. Expected %v and got %v.\n", test.Steps, steps)
+			}
+		})
+	}
+}
+
+func TestWindowBounds(t *testing.T) {
+	tests := []struct {
+		Name            string
+		PastTolerance   time.Duration
+		FutureTolerance time.Duration
+		PastSteps       int
+		StepSize        time.Duration
+		AcceptOffsets   []int
+		LastT           int
+		TMin            int
+		TMax            int
+	}{
+		{"No Window", 0, 0, 0, 0, nil, 0, 0x23523EC, 0x23523EC},
+		{"One Step Each Way", 30 * time.Second, 30 * time.Second, 0, 0, nil, 0, 0x23523EB, 0x23523ED},
+		{"Partial Step", 15 * time.Second, 15 * time.Second, 0, 0, nil, 0, 0x23523EC, 0x23523ED},
+		{"Past Steps", 0, 0, 3, 0, nil, 0, 0x23523E9, 0x23523EC},
+		{"Minute Step Size", time.Minute, 0, 0, time.Minute, nil, 0, 18518517, 18518518},
+		{"Accept Offsets", 0, 0, 0, 0, []int{2, -3}, 0, 0x23523E9, 0x23523EE},
+		{"LastT Not Applied", 30 * time.Second, 30 * time.Second, 0, 0, nil, 0x23523ED, 0x23523EB, 0x23523ED},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				PastTolerance:   test.PastTolerance,
+				FutureTolerance: test.FutureTolerance,
+				PastSteps:       test.PastSteps,
+				StepSize:        test.StepSize,
+				AcceptOffsets:   test.AcceptOffsets,
+				LastT:           test.LastT,
+			}
+
+			tMin, tMax := validator.WindowBounds(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC))
+			if tMin != test.TMin || tMax != test.TMax {
+				t.Errorf("Bounds did not match. Expected %d-%d and got %d-%d.\n", test.TMin, test.TMax, tMin, tMax)
+			}
+		})
+	}
+}
+
+func TestIsNearBoundary(t *testing.T) {
+	stepStart := time.Date(2005, 3, 18, 1, 58, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name      string
+		StepSize  time.Duration
+		Offset    time.Duration
+		Threshold time.Duration
+		Near      bool
+	}{
+		{"Start", 0, 0, 5 * time.Second, true},
+		{"Just Started", 0, 3 * time.Second, 5 * time.Second, true},
+		{"Middle", 0, 15 * time.Second, 5 * time.Second, false},
+		{"About To Change", 0, 27 * time.Second, 5 * time.Second, true},
+		{"Last Instant", 0, 30*time.Second - time.Nanosecond, 5 * time.Second, true},
+		{"Outside Threshold", 0, 24 * time.Second, 5 * time.Second, false},
+		{"Zero Threshold", 0, 29 * time.Second, 0, false},
+		{"Sub-Second Near", 100 * time.Millisecond, 95 * time.Millisecond, 10 * time.Millisecond, true},
+		{"Sub-Second Middle", 100 * time.Millisecond, 50 * time.Millisecond, 10 * time.Millisecond, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{StepSize: test.StepSize}
+
+			near := validator.IsNearBoundary(stepStart.Add(test.Offset), test.Threshold)
+			if near != test.Near {
+				t.Errorf("Near did not match. Expected %t and got %t.\n", test.Near, near)
+			}
+		})
+	}
+}
+
+func TestDetectStepSizeMismatch(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name       string
+		Code       int
+		Candidates []int
+		StepSize   int
+		Match      bool
+	}{
+		{"30 Seconds", TOTPCode(sha1.New, key, SixDigits, 30, now), []int{30, 60}, 30, true},
+		{"60 Seconds", TOTPCode(sha1.New, key, SixDigits, 60, now), []int{30, 60}, 60, true},
+		{"60 Seconds Not Candidate", TOTPCode(sha1.New, key, SixDigits, 60, now), []int{30, 15}, 0, false},
+		{"60 Seconds Default Candidates", TOTPCode(sha1.New, key, SixDigits, 60, now), nil, 60, true},
+		{"Invalid Candidates", TOTPCode(sha1.New, key, SixDigits, 30, now), []int{0, -30}, 0, false},
+		{"No Match", 123456, nil, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			stepSize, match := DetectStepSizeMismatch(key, sha1.New, SixDigits, now, test.Code, test.Candidates)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if stepSize != test.StepSize {
+				t.Errorf("Step size did not match. Expected %d and got %d.\n", test.StepSize, stepSize)
+			}
+		})
+	}
+}
+
+func TestDiagnosticTable(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	expected := `ALGORITHM  DIGITS  CODE
+SHA1       6       081804
+SHA1       7       7081804
+SHA1       8       07081804
+SHA256     6       ` + fmt.Sprintf("%06d", TOTPCode(sha256.New, key, SixDigits, 30, now)) + `
+SHA256     7       ` + fmt.Sprintf("%07d", TOTPCode(sha256.New, key, SevenDigits, 30, now)) + `
+SHA256     8       ` + fmt.Sprintf("%08d", TOTPCode(sha256.New, key, EightDigits, 30, now)) + `
+SHA512     6       ` + fmt.Sprintf("%06d", TOTPCode(sha512.New, key, SixDigits, 30, now)) + `
+SHA512     7       ` + fmt.Sprintf("%07d", TOTPCode(sha512.New, key, SevenDigits, 30, now)) + `
+SHA512     8       ` + fmt.Sprintf("%08d", TOTPCode(sha512.New, key, EightDigits, 30, now)) + `
+`
+
+	table := DiagnosticTable(key, now)
+	if table != expected {
+		t.Errorf("Table did not match. Expected:\n%s\nGot:\n%s", expected, table)
+	}
+}
+
+func TestEstimatedCost(t *testing.T) {
+	tests := []struct {
+		Name            string
+		PastTolerance   int
+		FutureTolerance int
+		Offsets         []int
+		LastT           int
+		FixedScanSteps  int
+		Cost            int
+	}{
+		{"No Window", 0, 0, nil, 0, 0, 1},
+		{"1 Window", 30, 30, nil, 0, 0, 3},
+		{"2 Window", 60, 60, nil, 0, 0, 5},
+		{"Offsets", 0, 0, []int{-1, 1}, 0, 0, 2},
+		{"LastT Skips", 30, 30, nil, 0x23523EC, 0, 1},
+		{"LastT Skips All", 30, 30, nil, 0x23523EE, 0, 0},
+		{"Fixed", 30, 30, nil, 0x23523EE, 10, 10},
+		{"Fixed Smaller Than Window", 120, 120, nil, 0, 3, 9},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				PastTolerance:   time.Duration(test.PastTolerance) * time.Second,
+				FutureTolerance: time.Duration(test.FutureTolerance) * time.Second,
+				AcceptOffsets:   test.Offsets,
+				LastT:           test.LastT,
+				FixedScanSteps:  test.FixedScanSteps,
+			}
+
+			testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+			cost := validator.EstimatedCost(testTime)
+			if cost != test.Cost {
+				t.Errorf("Cost did not match. Expected %d and got %d.\n", test.Cost, cost)
+			}
+
+			// every step is computed again for the previous key during its grace period
+			validator.PreviousKey = []byte("12345678901234567890")
+			validator.RotatedAt = testTime.Add(-time.Minute)
+			validator.RotationGrace = time.Hour
+			if cost := validator.EstimatedCost(testTime); cost != 2*test.Cost {
+				t.Errorf("Cost during grace did not match. Expected %d and got %d.\n", 2*test.Cost, cost)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package otp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Supported range of NewDigits.
+const (
+	MinDigits = 1
+	MaxDigits = 10
+)
+
+// NewDigits returns the Digits producing codes of n decimal digits.
+// n must be between MinDigits and MaxDigits.
+func NewDigits(n int) (Digits, error) {
+	if n < MinDigits || n > MaxDigits {
+		return 0, fmt.Errorf("otp: unsupported number of digits %d, must be between %d and %d", n, MinDigits, MaxDigits)
+	}
+
+	d := Digits(1)
+	for i := 0; i < n; i++ {
+		d *= 10
+	}
+	return d, nil
+}
+
+// Length returns the number of decimal digits in a code produced with d, the base 10
+// logarithm of the modulus. For example SixDigits has a Length of 6.
+// Length returns 0 for a zero Digits.
+func (d Digits) Length() int {
+	length := 0
+	for m := d; m > 1; m /= 10 {
+		length++
+	}
+	return length
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding d as its number of digits such
+// as "6" so configuration files are readable. An error is returned if d is not one of the
+// values returned by NewDigits.
+func (d Digits) MarshalText() ([]byte, error) {
+	length := d.Length()
+	if supported, err := NewDigits(length); err != nil || supported != d {
+		return nil, fmt.Errorf("otp: unsupported digits %d", uint64(d))
+	}
+	return []byte(strconv.Itoa(length)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing a number of digits as
+// encoded by MarshalText. Counts not supported by NewDigits are rejected.
+func (d *Digits) UnmarshalText(text []byte) error {
+	n, err := strconv.Atoi(string(text))
+	if err != nil {
+		return fmt.Errorf("otp: invalid digits %q", text)
+	}
+
+	digits, err := NewDigits(n)
+	if err != nil {
+		return err
+	}
+	*d = digits
+	return nil
+}
+
+// maxCodeValues is the number of values of the 31 bit truncated HMAC codes are derived from.
+const maxCodeValues = 1 << 31
+
+// maxInt is the largest value of an int on the target platform.
+const maxInt = int(^uint(0) >> 1)
+
+// GuaranteedUniqueValues returns the number of distinct codes d can produce.
+// As Digits is used as a modulus this is int(d), limited to the 2^31 values of the
+// truncated HMAC for TenDigits. Codes with leading zeros are included so the value does
+// not depend on how codes are displayed. On 32 bit platforms the 2^31 values of TenDigits
+// can't be represented and math.MaxInt32 is returned.
+func (d Digits) GuaranteedUniqueValues() int {
+	values := d.values()
+	if values > uint64(maxInt) {
+		return maxInt
+	}
+	return int(values)
+}
+
+// Bits returns the entropy of a single code in bits, the base 2 logarithm of the number
+// of distinct codes. For example a SixDigits code carries roughly 19.93 bits.
+func (d Digits) Bits() float64 {
+	if d == 0 {
+		return 0
+	}
+	return math.Log2(float64(d.values()))
+}
+
+// values returns the number of distinct codes d can produce.
+func (d Digits) values() uint64 {
+	if d > maxCodeValues {
+		return maxCodeValues
+	}
+	return uint64(d)
+}
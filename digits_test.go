@@ -0,0 +1,210 @@
+package otp
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDigitsEntropy(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Digits Digits
+		Values int64
+		Bits   float64
+	}{
+		{"Zero", 0, 0, 0},
+		{"Six", SixDigits, 1000000, 19.9316},
+		{"Seven", SevenDigits, 10000000, 23.2535},
+		{"Eight", EightDigits, 100000000, 26.5754},
+		{"Nine", NineDigits, 1000000000, 29.8974},
+		{"Ten", TenDigits, 1 << 31, 31},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			expected := test.Values
+			if expected > int64(maxInt) {
+				// capped on 32 bit platforms
+				expected = int64(maxInt)
+			}
+
+			values := test.Digits.GuaranteedUniqueValues()
+			if int64(values) != expected {
+				t.Errorf("Values did not match. Expected %d and got %d.\n", expected, values)
+			}
+
+			bits := test.Digits.Bits()
+			if math.Abs(bits-test.Bits) > 0.0001 {
+				t.Errorf("Bits did not match. Expected %f and got %f.\n", test.Bits, bits)
+			}
+		})
+	}
+}
+
+func TestDigitsLength(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Digits Digits
+		Length int
+	}{
+		{"Zero", 0, 0},
+		{"One", 1, 0},
+		{"Ten", 10, 1},
+		{"Four", 10000, 4},
+		{"Six", SixDigits, 6},
+		{"Seven", SevenDigits, 7},
+		{"Eight", EightDigits, 8},
+		{"Nine", NineDigits, 9},
+		{"Ten", TenDigits, 10},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			length := test.Digits.Length()
+			if length != test.Length {
+				t.Errorf("Length did not match. Expected %d and got %d.\n", test.Length, length)
+			}
+		})
+	}
+}
+
+func TestNewDigits(t *testing.T) {
+	tests := []struct {
+		Name   string
+		N      int
+		Digits Digits
+		Err    bool
+	}{
+		{"Zero", 0, 0, true},
+		{"Negative", -6, 0, true},
+		{"One", 1, 10, false},
+		{"Four", 4, 10000, false},
+		{"Six", 6, SixDigits, false},
+		{"Seven", 7, SevenDigits, false},
+		{"Eight", 8, EightDigits, false},
+		{"Nine", 9, NineDigits, false},
+		{"Ten", 10, TenDigits, false},
+		{"Eleven", 11, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			d, err := NewDigits(test.N)
+			if (err != nil) != test.Err {
+				t.Fatalf("Error did not match. Expected error %t and got %v.\n", test.Err, err)
+			}
+			if d != test.Digits {
+				t.Errorf("Digits did not match. Expected %d and got %d.\n", test.Digits, d)
+			}
+			if err == nil && d.Length() != test.N {
+				t.Errorf("Length did not round trip. Expected %d and got %d.\n", test.N, d.Length())
+			}
+		})
+	}
+}
+
+func TestDigitsConstantTypes(t *testing.T) {
+	digitsType := reflect.TypeOf(Digits(0))
+
+	for name, value := range map[string]interface{}{
+		"SixDigits":   SixDigits,
+		"SevenDigits": SevenDigits,
+		"EightDigits": EightDigits,
+		"NineDigits":  NineDigits,
+		"TenDigits":   TenDigits,
+	} {
+		if typ := reflect.TypeOf(value); typ != digitsType {
+			t.Errorf("%s has type %s, expected %s", name, typ, digitsType)
+		}
+	}
+}
+
+func TestDigitsMarshalText(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Digits Digits
+		Text   string
+		Err    bool
+	}{
+		{"One", 10, "1", false},
+		{"Six", SixDigits, "6", false},
+		{"Eight", EightDigits, "8", false},
+		{"Ten", TenDigits, "10", false},
+		{"Zero", 0, "", true},
+		{"Not A Power Of Ten", 123, "", true},
+		{"Too Many", TenDigits * 10, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			text, err := test.Digits.MarshalText()
+			if (err != nil) != test.Err {
+				t.Errorf("Error did not match. Expected %t and got %v.\n", test.Err, err)
+			}
+			if string(text) != test.Text {
+				t.Errorf("Text did not match. Expected %q and got %q.\n", test.Text, text)
+			}
+		})
+	}
+}
+
+func TestDigitsUnmarshalText(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Text   string
+		Digits Digits
+		Err    bool
+	}{
+		{"Six", "6", SixDigits, false},
+		{"Ten", "10", TenDigits, false},
+		{"Zero", "0", 0, true},
+		{"Eleven", "11", 0, true},
+		{"Modulus", "1000000", 0, true},
+		{"Not A Number", "six", 0, true},
+		{"Empty", "", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var digits Digits
+			err := digits.UnmarshalText([]byte(test.Text))
+			if (err != nil) != test.Err {
+				t.Errorf("Error did not match. Expected %t and got %v.\n", test.Err, err)
+			}
+			if digits != test.Digits {
+				t.Errorf("Digits did not match. Expected %d and got %d.\n", test.Digits, digits)
+			}
+		})
+	}
+}
+
+func TestDigitsJSON(t *testing.T) {
+	type config struct {
+		Digits Digits            `json:"digits"`
+		ByName map[string]Digits `json:"byName"`
+	}
+
+	data, err := json.Marshal(config{EightDigits, map[string]Digits{"bank": SixDigits}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	expected := `{"digits":"8","byName":{"bank":"6"}}`
+	if string(data) != expected {
+		t.Errorf("JSON did not match. Expected %s and got %s.\n", expected, data)
+	}
+
+	var decoded config
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if decoded.Digits != EightDigits || decoded.ByName["bank"] != SixDigits {
+		t.Errorf("Digits did not match. Expected %d and %d and got %d and %d.\n", EightDigits, SixDigits, decoded.Digits, decoded.ByName["bank"])
+	}
+
+	if err := json.Unmarshal([]byte(`{"digits":"12"}`), &decoded); err == nil {
+		t.Error("Expected an error for unsupported digits")
+	}
+}
@@ -0,0 +1,29 @@
+package otp
+
+import (
+	"time"
+)
+
+// CodeDistribution returns how many of the count codes for consecutive time steps from
+// the one containing start have each leading digit, counting leading zeros. The codes of
+// a working key and algorithm are close to uniformly distributed so a heavily skewed
+// result indicates a broken key or hash provider. The HMAC is keyed once for all codes.
+// cfg.Key is ignored in favour of key. CodeDistribution panics if HOTPCodeErr would return
+// an error.
+func CodeDistribution(key []byte, cfg Config, start time.Time, count int) map[int]int {
+	digits := cfg.digits()
+	g := mustHOTPGenerator(cfg.hashProvider(), key, digits)
+
+	// the place value of the leading digit
+	leading := int(uint64(digits) / 10)
+	if leading == 0 {
+		leading = 1
+	}
+
+	distribution := make(map[int]int)
+	step := int64(timeSteps(cfg.stepSizeSeconds(), start))
+	for i := 0; i < count; i++ {
+		distribution[g.code(step+int64(i))/leading]++
+	}
+	return distribution
+}
@@ -0,0 +1,66 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"hash"
+	"testing"
+	"time"
+)
+
+// constantHash is a broken hash whose digest doesn't depend on its input.
+type constantHash struct {
+	hash.Hash
+}
+
+func (h constantHash) Sum(b []byte) []byte {
+	return append(b, make([]byte, h.Size())...)
+}
+
+func TestCodeDistribution(t *testing.T) {
+	key := []byte("12345678901234567890")
+	start := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name    string
+		Config  Config
+		Count   int
+		Buckets int
+	}{
+		{"Six Digits", Config{}, 10000, 10},
+		{"One Digit", Config{Digits: Digits(10)}, 1000, 10},
+		{"Broken Hash", Config{HashProvider: func() hash.Hash { return constantHash{sha1.New()} }}, 1000, 1},
+		{"No Codes", Config{}, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			distribution := CodeDistribution(key, test.Config, start, test.Count)
+			if len(distribution) != test.Buckets {
+				t.Errorf("Buckets did not match. Expected %d and got %d: %v.\n", test.Buckets, len(distribution), distribution)
+			}
+
+			total := 0
+			for digit, n := range distribution {
+				if digit < 0 || digit > 9 {
+					t.Errorf("Leading digit %d out of range", digit)
+				}
+				// a uniform distribution puts a tenth of the codes in each bucket
+				if test.Buckets == 10 && (n < test.Count/20 || n > test.Count*3/20) {
+					t.Errorf("Leading digit %d is skewed with %d of %d codes", digit, n, test.Count)
+				}
+				total += n
+			}
+			if total != test.Count {
+				t.Errorf("Total did not match. Expected %d and got %d.\n", test.Count, total)
+			}
+		})
+	}
+}
+
+func TestCodeDistributionLeadingZeros(t *testing.T) {
+	// 081804 at T has a leading zero
+	distribution := CodeDistribution([]byte("12345678901234567890"), Config{}, time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), 1)
+	if distribution[0] != 1 {
+		t.Errorf("Leading zero was not counted: %v", distribution)
+	}
+}
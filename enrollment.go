@@ -0,0 +1,81 @@
+package otp
+
+import (
+	"errors"
+	"time"
+)
+
+// Enrollment is everything needed to enroll a key in an authenticator app.
+type Enrollment struct {
+	Secret       []byte
+	SecretBase32 string
+	URI          string
+	KeyURI       KeyURI
+}
+
+// NewEnrollment returns the enrollment of the key described by cfg for account at issuer.
+// When cfg has no Key a random secret the size of the HMAC's output, as recommended by
+// RFC 6238, is generated. Otherwise Secret shares cfg.Key. An error is returned if account
+// is empty or the secret can't be generated.
+func NewEnrollment(issuer, account string, cfg Config) (*Enrollment, error) {
+	if account == "" {
+		return nil, errors.New("otp: enrollment requires an account name")
+	}
+
+	secret := cfg.Key
+	if len(secret) == 0 {
+		size := cfg.hashProvider()().Size()
+		if size < MinSecretBytes {
+			size = MinSecretBytes
+		}
+
+		var err error
+		if secret, err = GenerateSecret(size); err != nil {
+			return nil, err
+		}
+	}
+
+	k := KeyURI{
+		Issuer:          issuer,
+		Account:         account,
+		Key:             secret,
+		Digits:          cfg.Digits,
+		StepSizeSeconds: cfg.StepSizeSeconds,
+		HashProvider:    cfg.HashProvider,
+	}
+
+	return &Enrollment{
+		Secret:       secret,
+		SecretBase32: secretEncoding.EncodeToString(secret),
+		URI:          k.String(),
+		KeyURI:       k,
+	}, nil
+}
+
+// QRCode returns a size by size pixel PNG image of a QR code encoding URI as
+// KeyURI.QRCode does.
+func (e *Enrollment) QRCode(size int) ([]byte, error) {
+	return e.KeyURI.QRCode(size)
+}
+
+// enrollmentTolerance returns the extra tolerance granted to a recently enrolled token.
+// Clients often have poorly synchronised clocks when first set up, so for EnrollmentAge
+// after EnrolledAt both the past and future tolerance are widened to at least the
+// returned duration. It starts at EnrollmentTolerance and ramps down linearly to zero
+// as the token ages. Zero is returned when enrollment leniency is not configured.
+func (tc *TOTPValidator) enrollmentTolerance(now time.Time) time.Duration {
+	if tc.EnrolledAt.IsZero() || tc.EnrollmentAge <= 0 || tc.EnrollmentTolerance <= 0 {
+		return 0
+	}
+
+	age := now.Sub(tc.EnrolledAt)
+	if age < 0 {
+		age = 0
+	}
+	if age >= tc.EnrollmentAge {
+		return 0
+	}
+
+	remaining := float64(tc.EnrollmentAge-age) / float64(tc.EnrollmentAge)
+	return time.Duration(float64(tc.EnrollmentTolerance) * remaining)
+}
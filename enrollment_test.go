@@ -0,0 +1,144 @@
+package otp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func TestEnrollmentTolerance(t *testing.T) {
+	enrolledAt := time.Date(2005, 3, 18, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name      string
+		Age       time.Duration
+		Tolerance time.Duration
+	}{
+		{"Before Enrollment", -time.Hour, 10 * time.Minute},
+		{"At Enrollment", 0, 10 * time.Minute},
+		{"Quarter", 6 * time.Hour, 7*time.Minute + 30*time.Second},
+		{"Half", 12 * time.Hour, 5 * time.Minute},
+		{"Three Quarters", 18 * time.Hour, 2*time.Minute + 30*time.Second},
+		{"Expired", 24 * time.Hour, 0},
+		{"Long Expired", 240 * time.Hour, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				EnrolledAt:          enrolledAt,
+				EnrollmentAge:       24 * time.Hour,
+				EnrollmentTolerance: 10 * time.Minute,
+			}
+
+			tolerance := validator.enrollmentTolerance(enrolledAt.Add(test.Age))
+			if tolerance != test.Tolerance {
+				t.Errorf("Tolerance did not match. Expected %s and got %s.\n", test.Tolerance, tolerance)
+			}
+		})
+	}
+}
+
+func TestEnrollmentLeniency(t *testing.T) {
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name          string
+		EnrolledAt    time.Time
+		PastTolerance time.Duration
+		Code          int
+		Match         bool
+	}{
+		{"T-2 Not Configured", time.Time{}, 0, 48150727, false},
+		{"T-2 Young", testTime.Add(-time.Hour), 0, 48150727, true},
+		{"T+2 Young", testTime.Add(-time.Hour), 0, 44266759, true},
+		{"T-2 Ramped Down", testTime.Add(-18 * time.Hour), 0, 48150727, false},
+		{"T-1 Ramped Down", testTime.Add(-18 * time.Hour), 0, 89731029, true},
+		{"T-1 Mature", testTime.Add(-48 * time.Hour), 0, 89731029, false},
+		{"T-1 Mature Tolerance", testTime.Add(-48 * time.Hour), 30 * time.Second, 89731029, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:                 []byte("12345678901234567890"),
+				Digits:              EightDigits,
+				PastTolerance:       test.PastTolerance,
+				EnrolledAt:          test.EnrolledAt,
+				EnrollmentAge:       24 * time.Hour,
+				EnrollmentTolerance: 2 * time.Minute,
+			}
+
+			match, _ := validator.ValidateTOTPCode(testTime, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+		})
+	}
+}
+
+func TestNewEnrollment(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name        string
+		Account     string
+		Config      Config
+		SecretBytes int
+		URI         string
+		Err         bool
+	}{
+		{"Key", "alice", Config{Key: key}, 20,
+			"otpauth://totp/Example:alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&issuer=Example&algorithm=SHA1&digits=6&period=30", false},
+		{"Generated SHA1", "alice", Config{}, 20, "", false},
+		{"Generated SHA256", "alice", Config{HashProvider: sha256.New, Digits: EightDigits}, 32, "", false},
+		{"No Account", "", Config{Key: key}, 0, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			enrollment, err := NewEnrollment("Example", test.Account, test.Config)
+			if test.Err {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(enrollment.Secret) != test.SecretBytes {
+				t.Errorf("Secret size did not match. Expected %d and got %d.\n", test.SecretBytes, len(enrollment.Secret))
+			}
+			if secret, err := DecodeSecret(enrollment.SecretBase32); err != nil || !bytes.Equal(secret, enrollment.Secret) {
+				t.Errorf("SecretBase32 %s did not decode to the secret: %v", enrollment.SecretBase32, err)
+			}
+			if test.URI != "" && enrollment.URI != test.URI {
+				t.Errorf("URI did not match. Expected %s and got %s.\n", test.URI, enrollment.URI)
+			}
+
+			// the URI enrolls the secret with the config
+			validator, _, _, err := ParseURI(enrollment.URI)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+			cfg := test.Config
+			cfg.Key = enrollment.Secret
+			if ok, _ := validator.ValidateTOTPCode(now, cfg.Code(now)); !ok {
+				t.Error("Code for the config was rejected by the enrolled validator")
+			}
+
+			qr, err := enrollment.QRCode(256)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, err := png.Decode(bytes.NewReader(qr)); err != nil {
+				t.Errorf("QR code is not a PNG: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package otp
+
+import (
+	"hash"
+	"time"
+)
+
+// unixEpoch is the default T0 of RFC 6238.
+var unixEpoch = time.Unix(0, 0)
+
+// TOTPCodeAt generates a Time-Based One-Time Password like TOTPCode but counting time
+// steps from t0 rather than the Unix epoch, the T0 parameter of RFC 6238. A zero t0 is
+// the Unix epoch.
+func TOTPCodeAt(hashProvider func() hash.Hash, key []byte, digits Digits, stepSizeSeconds int, t0 time.Time, t time.Time) int {
+	return HOTPCode(hashProvider, key, digits, int64(timeSteps(stepSizeSeconds, t.Add(-epochOffset(t0)))))
+}
+
+// epochOffset returns how far epoch is after the Unix epoch, treating the zero time as the
+// Unix epoch.
+func epochOffset(epoch time.Time) time.Duration {
+	if epoch.IsZero() {
+		return 0
+	}
+	return epoch.Sub(unixEpoch)
+}
+
+// stepAt returns the time step containing now for the given step size, counted from the
+// validator's Epoch.
+func (tc *TOTPValidator) stepAt(stepSize time.Duration, now time.Time) int {
+	return durationSteps(stepSize, now.Add(-epochOffset(tc.Epoch)))
+}
+
+// stepBegins returns the instant time step t begins for the given step size, counted from
+// the validator's Epoch.
+func (tc *TOTPValidator) stepBegins(stepSize time.Duration, t int) time.Time {
+	return stepStart(stepSize, t).Add(epochOffset(tc.Epoch))
+}
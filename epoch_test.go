@@ -0,0 +1,68 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestTOTPCodeAt(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name  string
+		Epoch time.Time
+		Code  int
+	}{
+		{"Zero Epoch", time.Time{}, 7081804},
+		{"Unix Epoch", time.Unix(0, 0), 7081804},
+		// shifting T0 by one step selects the code of T-1
+		{"One Step", time.Unix(30, 0), 89731029},
+		{"Part Step", time.Unix(45, 0), 89731029},
+		{"Before Unix Epoch", time.Unix(-30, 0), 14050471},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code := TOTPCodeAt(sha1.New, key, EightDigits, 30, test.Epoch, now)
+			if code != test.Code {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", test.Code, code)
+			}
+		})
+	}
+}
+
+func TestValidatorEpoch(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	validator := &TOTPValidator{
+		Key:    key,
+		Digits: EightDigits,
+		Epoch:  epoch,
+	}
+
+	code := TOTPCodeAt(sha1.New, key, EightDigits, 30, epoch, now)
+	ok, tMatch := validator.ValidateTOTPCode(now, code)
+	if !ok {
+		t.Fatal("Code did not match")
+	}
+
+	expectedT := int(now.Sub(epoch) / (30 * time.Second))
+	if tMatch != expectedT {
+		t.Errorf("T did not match. Expected %d and got %d.\n", expectedT, tMatch)
+	}
+
+	if ok, _ := validator.ValidateTOTPCode(now, 7081804); ok {
+		t.Error("Code for the Unix epoch matched")
+	}
+
+	validator.LastT = 0
+	until, ok := validator.ValidUntil(now, code)
+	expected := time.Date(2005, 3, 18, 1, 58, 30, 0, time.UTC)
+	if !ok || !until.Equal(expected) {
+		t.Errorf("ValidUntil did not match. Expected %s and got %s.\n", expected, until)
+	}
+}
@@ -0,0 +1,73 @@
+package otp
+
+import (
+	"hash"
+	"time"
+)
+
+// TOTPCodeWithExpiry generates a TOTP code like TOTPCode along with validUntil, the end of
+// the time step containing t when the next code takes over. Both are derived from the
+// same time step so they always agree. A validator with a past tolerance continues to
+// accept the code after validUntil; see TOTPValidator.ValidUntil.
+func TOTPCodeWithExpiry(hashProvider func() hash.Hash, key []byte, digits Digits, stepSizeSeconds int, t time.Time) (code int, validUntil time.Time) {
+	step := timeSteps(stepSizeSeconds, t)
+	code = HOTPCode(hashProvider, key, digits, int64(step))
+	validUntil = TimeForStep(stepSizeSeconds, step+1).In(t.Location())
+	return code, validUntil
+}
+
+// ValidUntil validates code like ValidateTOTPCode and, if it is valid, returns the instant
+// from which it will no longer be accepted as the validator's tolerance window moves past
+// the matched step. This lets a push notification carrying a code expire at the right time.
+// The window is taken as capped at MaxWindowSteps, which can end acceptance before the
+// past tolerance has passed. Any extra tolerance granted to newly enrolled tokens is
+// ignored so the result is conservative. The zero time is returned if code is not valid.
+func (tc *TOTPValidator) ValidUntil(now time.Time, code int) (time.Time, bool) {
+	ok, t := tc.ValidateTOTPCode(now, code)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	stepSize := tc.stepSize()
+
+	if len(tc.AcceptOffsets) > 0 {
+		minOffset := tc.AcceptOffsets[0]
+		for _, offset := range tc.AcceptOffsets {
+			if offset < minOffset {
+				minOffset = offset
+			}
+		}
+		return tc.stepBegins(stepSize, t-minOffset+1).In(now.Location()), true
+	}
+
+	return tc.windowPasses(stepSize, now, t).In(now.Location()), true
+}
+
+// windowPasses returns the first instant from now at which the window, capped at
+// MaxWindowSteps, begins after step t. The past tolerance leaving t behind bounds it, and
+// before then the capped window can only move when now or either end of the tolerances
+// crosses a step boundary, so only those instants are checked.
+func (tc *TOTPValidator) windowPasses(stepSize time.Duration, now time.Time, t int) time.Time {
+	pastTolerance, futureTolerance := tc.pastTolerance(), tc.futureTolerance()
+	limit := tc.stepBegins(stepSize, t+1).Add(pastTolerance)
+
+	for at := now; at.Before(limit); {
+		current := tc.stepAt(stepSize, at)
+		tMin := tc.stepAt(stepSize, at.Add(-pastTolerance))
+		tMax := tc.stepAt(stepSize, at.Add(futureTolerance))
+		if first, _ := tc.clampWindow(current, tMin, tMax); first > t {
+			return at
+		}
+
+		next := limit
+		for _, offset := range []time.Duration{-pastTolerance, 0, futureTolerance} {
+			boundary := tc.stepBegins(stepSize, tc.stepAt(stepSize, at.Add(offset))+1).Add(-offset)
+			if boundary.Before(next) {
+				next = boundary
+			}
+		}
+		at = next
+	}
+
+	return limit
+}
@@ -0,0 +1,102 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestValidUntil(t *testing.T) {
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name          string
+		Code          int
+		PastTolerance time.Duration
+		Offsets       []int
+		LastT         int
+		Valid         bool
+		Until         time.Time
+	}{
+		{"T No Window", 7081804, 0, nil, 0, true, time.Date(2005, 3, 18, 1, 58, 30, 0, time.UTC)},
+		{"T 1 Window", 7081804, 30 * time.Second, nil, 0, true, time.Date(2005, 3, 18, 1, 59, 0, 0, time.UTC)},
+		{"T-1 1 Window", 89731029, 30 * time.Second, nil, 0, true, time.Date(2005, 3, 18, 1, 58, 30, 0, time.UTC)},
+		{"T-1 .5 Window", 89731029, 45 * time.Second, nil, 0, true, time.Date(2005, 3, 18, 1, 58, 45, 0, time.UTC)},
+		// MaxWindowSteps keeps 8 past steps so the code expires long before the tolerance
+		{"T Capped Window", 7081804, 10 * time.Minute, nil, 0, true, time.Date(2005, 3, 18, 2, 2, 30, 0, time.UTC)},
+		{"T Offsets", 7081804, 0, []int{1, -2, 0}, 0, true, time.Date(2005, 3, 18, 1, 59, 30, 0, time.UTC)},
+		{"No Match", 7081803, 30 * time.Second, nil, 0, false, time.Time{}},
+		{"T LastT", 7081804, 30 * time.Second, nil, 0x23523EC, false, time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastTolerance:   test.PastTolerance,
+				FutureTolerance: 30 * time.Second,
+				AcceptOffsets:   test.Offsets,
+				LastT:           test.LastT,
+			}
+
+			until, valid := validator.ValidUntil(testTime, test.Code)
+			if valid != test.Valid {
+				t.Errorf("Valid did not match. Expected %t and got %t.\n", test.Valid, valid)
+			}
+			if !until.Equal(test.Until) {
+				t.Errorf("Until did not match. Expected %s and got %s.\n", test.Until, until)
+			}
+
+			if valid {
+				if ok, _ := validator.ValidateTOTPCode(until.Add(-time.Nanosecond), test.Code); !ok {
+					t.Error("Code was not valid just before until")
+				}
+				if ok, _ := validator.ValidateTOTPCode(until, test.Code); ok {
+					t.Error("Code was valid at until")
+				}
+			}
+		})
+	}
+}
+
+func TestTOTPCodeWithExpiry(t *testing.T) {
+	key := []byte("12345678901234567890")
+	stepEnd := time.Date(2005, 3, 18, 1, 58, 30, 0, time.UTC)
+
+	tests := []struct {
+		Name            string
+		Time            time.Time
+		StepSizeSeconds int
+		Code            int
+		ValidUntil      time.Time
+	}{
+		{"Mid Step", stepEnd.Add(-time.Second), 30, 7081804, stepEnd},
+		{"Step Start", stepEnd.Add(-30 * time.Second), 30, 7081804, stepEnd},
+		{"Last Instant", stepEnd.Add(-time.Nanosecond), 30, 7081804, stepEnd},
+		{"Next Step", stepEnd, 30, 14050471, stepEnd.Add(30 * time.Second)},
+		{"Minute Step", stepEnd.Add(-time.Second), 60, HOTPCode(sha1.New, key, EightDigits, 18518518), stepEnd.Add(30 * time.Second)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code, validUntil := TOTPCodeWithExpiry(sha1.New, key, EightDigits, test.StepSizeSeconds, test.Time)
+			if code != test.Code {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", test.Code, code)
+			}
+			if !validUntil.Equal(test.ValidUntil) {
+				t.Errorf("Valid until did not match. Expected %v and got %v.\n", test.ValidUntil, validUntil)
+			}
+		})
+	}
+}
+
+func TestTOTPCodeWithExpiryLocation(t *testing.T) {
+	location := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2005, 3, 18, 3, 58, 29, 0, location)
+
+	_, validUntil := TOTPCodeWithExpiry(sha1.New, []byte("12345678901234567890"), SixDigits, 30, now)
+	if validUntil.Location() != location {
+		t.Errorf("Location did not match. Expected %v and got %v.\n", location, validUntil.Location())
+	}
+}
@@ -0,0 +1,90 @@
+package otp
+
+import (
+	"errors"
+	"time"
+)
+
+// Reasons a code is rejected, as returned by ValidateTOTPCodeErr.
+var (
+	ErrCodeMismatch   = errors.New("otp: code does not match")
+	ErrCodeReused     = errors.New("otp: code has already been used")
+	ErrCodeFromFuture = errors.New("otp: code is for a time after the accepted window")
+	ErrCodeFromPast   = errors.New("otp: code is for a time before the accepted window")
+)
+
+// failureSearchSteps is how many time steps beyond each side of the window
+// ValidateTOTPCodeErr searches to diagnose a rejected code.
+const failureSearchSteps = 10
+
+// ValidateTOTPCodeErr validates code like ValidateTOTPCode, returning the time step it
+// matched and a nil error on success. A rejected code is diagnosed from the steps around
+// the window: ErrCodeReused is returned for a code matching a used step in the window and
+// ErrCodeFromFuture or ErrCodeFromPast for one matching a step up to 10 steps after or
+// before the window, along with that step. Otherwise ErrCodeMismatch is returned with the
+// current time step. Like ValidateTOTPCode it only reads the validator.
+//
+// The diagnosis reveals which other time step a code is valid for, so a code reported as
+// ErrCodeFromFuture will later be accepted. Report the reason to the user who entered the
+// code, as ExplainFailure does, and limit attempts as for any validation.
+func (tc *TOTPValidator) ValidateTOTPCodeErr(now time.Time, code int) (int, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if ok, t := tc.validate(now, code); ok {
+		return t, nil
+	}
+
+	generators := tc.generators(now)
+	matches := func(t int) bool {
+		matched := 0
+		for _, g := range generators {
+			matched |= codesEqual(g.code(int64(t)), code)
+		}
+		return matched == 1
+	}
+
+	steps := tc.steps(now)
+	for _, t := range steps {
+		if tc.used(t) && matches(t) {
+			return t, ErrCodeReused
+		}
+	}
+
+	if len(steps) == 0 {
+		return tc.stepAt(tc.stepSize(), now), ErrCodeMismatch
+	}
+
+	first, last := steps[0], steps[len(steps)-1]
+	for i := 1; i <= failureSearchSteps; i++ {
+		if matches(last + i) {
+			return last + i, ErrCodeFromFuture
+		}
+		if matches(first - i) {
+			return first - i, ErrCodeFromPast
+		}
+	}
+
+	return tc.stepAt(tc.stepSize(), now), ErrCodeMismatch
+}
+
+// ExplainFailure returns a message for the user who entered a rejected code describing
+// err, an error returned by a validation, and how to remedy it. An empty string is
+// returned for a nil err and a generic message for errors without specific advice.
+func ExplainFailure(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrCodeMismatch):
+		return "The code is incorrect. Check that you entered the code for this account and try again."
+	case errors.Is(err, ErrCodeReused):
+		return "This code has already been used. Wait for your app to show a new code and try again."
+	case errors.Is(err, ErrCodeFromFuture):
+		return "Your device's clock may be ahead. Check that its time is set automatically and try again."
+	case errors.Is(err, ErrCodeFromPast):
+		return "The code has expired or your device's clock may be behind. Check that its time is set automatically and enter the current code."
+	case errors.Is(err, ErrRateLimited):
+		return "Too many attempts. Wait a few minutes before trying again."
+	}
+	return "The code could not be verified. Try again."
+}
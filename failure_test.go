@@ -0,0 +1,77 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestValidateTOTPCodeErr(t *testing.T) {
+	key := []byte("12345678901234567890")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name  string
+		Code  int
+		LastT int
+		T     int
+		Err   error
+	}{
+		{"Match", 7081804, 0, 0x23523EC, nil},
+		{"Within Window", 89731029, 0, 0x23523EB, nil},
+		{"Reused", 7081804, 0x23523EC, 0x23523EC, ErrCodeReused},
+		{"From Future", HOTPCode(sha1.New, key, EightDigits, 0x23523F0), 0, 0x23523F0, ErrCodeFromFuture},
+		{"From Past", HOTPCode(sha1.New, key, EightDigits, 0x23523E5), 0, 0x23523E5, ErrCodeFromPast},
+		{"Beyond Search", HOTPCode(sha1.New, key, EightDigits, 0x23523EC+12), 0, 0x23523EC, ErrCodeMismatch},
+		{"Mismatch", 7081803, 0, 0x23523EC, ErrCodeMismatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             key,
+				Digits:          EightDigits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+				LastT:           test.LastT,
+			}
+
+			tMatch, err := validator.ValidateTOTPCodeErr(testTime, test.Code)
+			if err != test.Err {
+				t.Errorf("Error did not match. Expected %v and got %v.\n", test.Err, err)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+			if !validator.LastUsed().IsZero() {
+				t.Error("ValidateTOTPCodeErr recorded a success")
+			}
+		})
+	}
+}
+
+func TestExplainFailure(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Err     error
+		Message string
+	}{
+		{"Nil", nil, ""},
+		{"Mismatch", ErrCodeMismatch, "The code is incorrect. Check that you entered the code for this account and try again."},
+		{"Reused", ErrCodeReused, "This code has already been used. Wait for your app to show a new code and try again."},
+		{"Future", ErrCodeFromFuture, "Your device's clock may be ahead. Check that its time is set automatically and try again."},
+		{"Past", ErrCodeFromPast, "The code has expired or your device's clock may be behind. Check that its time is set automatically and enter the current code."},
+		{"Rate Limited", ErrRateLimited, "Too many attempts. Wait a few minutes before trying again."},
+		{"Wrapped", fmt.Errorf("login: %w", ErrCodeReused), "This code has already been used. Wait for your app to show a new code and try again."},
+		{"Other", ErrEmptyKey, "The code could not be verified. Try again."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if message := ExplainFailure(test.Err); message != test.Message {
+				t.Errorf("Message did not match. Expected %q and got %q.\n", test.Message, message)
+			}
+		})
+	}
+}
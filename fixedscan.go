@@ -0,0 +1,36 @@
+package otp
+
+import (
+	"crypto/subtle"
+	"time"
+)
+
+// validateFixedScan is ValidateTOTPCode for validators with FixedScanSteps set.
+// Every step in the window has its code computed and compared in constant time, including
+// used steps, and dummy codes are computed until FixedScanSteps codes have
+// been computed. A window wider than FixedScanSteps is still scanned in full.
+// This costs FixedScanSteps HMAC computations per validation however early a code matches.
+func (tc *TOTPValidator) validateFixedScan(now time.Time, code int) (bool, int) {
+	current := tc.stepAt(tc.stepSize(), now)
+	steps := tc.steps(now)
+
+	matched := 0
+	tMatch := current
+	for _, g := range tc.generators(now) {
+		for _, t := range steps {
+			isMatch := codesEqual(g.code(int64(t)), code)
+			if tc.used(t) {
+				isMatch = 0
+			}
+
+			tMatch = subtle.ConstantTimeSelect(isMatch&^matched, t, tMatch)
+			matched |= isMatch
+		}
+
+		for i := len(steps); i < tc.FixedScanSteps; i++ {
+			g.code(int64(current))
+		}
+	}
+
+	return matched == 1, tMatch
+}
@@ -0,0 +1,96 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"hash"
+	"testing"
+	"time"
+)
+
+func TestFixedScanSteps(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Code            int
+		Match           bool
+		T               int
+		LastT           int
+		PastTolerance   int
+		FutureTolerance int
+	}{
+		{"T-1 Match No Window", 89731029, false, 0x23523EC, 0, 0, 0},
+		{"T Match No Window", 7081804, true, 0x23523EC, 0, 0, 0},
+		{"T-1 Match 1 Window", 89731029, true, 0x23523EB, 0, 30, 30},
+		{"T Match 1 Window", 7081804, true, 0x23523EC, 0, 30, 30},
+		{"T+1 Match 1 Window", 14050471, true, 0x23523ED, 0, 30, 30},
+		{"T+2 Match 1 Window", 44266759, false, 0x23523EC, 0, 30, 30},
+		{"T+2 Match Wider Than Fixed", 44266759, true, 0x23523EE, 0, 120, 120},
+		{"T Match 1 Window LastT", 7081804, false, 0x23523EC, 0x23523EC, 30, 30},
+		{"T+1 Match 1 Window LastT", 14050471, true, 0x23523ED, 0x23523EC, 30, 30},
+		{"Negative Code", -7081804, false, 0x23523EC, 0, 30, 30},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastTolerance:   time.Duration(test.PastTolerance) * time.Second,
+				FutureTolerance: time.Duration(test.FutureTolerance) * time.Second,
+				LastT:           test.LastT,
+				FixedScanSteps:  5,
+			}
+
+			testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+			match, tMatch := validator.ValidateTOTPCode(testTime, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
+
+func TestFixedScanStepsCost(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Code  int
+		LastT int
+	}{
+		{"Match First Step", 89731029, 0},
+		{"Match Last Step", 14050471, 0},
+		{"No Match", 7081803, 0},
+		{"All Steps Used", 7081804, 0x23523EE},
+	}
+
+	counts := make(map[int]bool)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sums := 0
+			validator := &TOTPValidator{
+				Key: []byte("12345678901234567890"),
+				HashProvider: func() hash.Hash {
+					return summingHash{sha1.New(), &sums}
+				},
+				Digits:          EightDigits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+				LastT:           test.LastT,
+				FixedScanSteps:  10,
+			}
+
+			validator.ValidateTOTPCode(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), test.Code)
+			// each HMAC sums the inner and outer hash
+			if hmacs := sums / 2; hmacs != validator.FixedScanSteps {
+				t.Errorf("HMAC computations did not match. Expected %d and got %d.\n", validator.FixedScanSteps, hmacs)
+			}
+			counts[sums] = true
+		})
+	}
+
+	if len(counts) != 1 {
+		t.Errorf("Validation cost varied: %v", counts)
+	}
+}
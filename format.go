@@ -0,0 +1,56 @@
+package otp
+
+import (
+	"hash"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatOptions configures FormatCode.
+type FormatOptions struct {
+	// Pad zero pads the code to the full width of its Digits.
+	Pad bool
+	// GroupSize splits the code into groups of this many digits from the left. 0 disables grouping.
+	GroupSize int
+	// Separator is placed between groups, for example " " or a non-breaking space "\u00a0".
+	Separator string
+}
+
+// FormatCode formats code for display. With Pad set the code is zero padded to the width
+// implied by digits so 81804 is shown as "081804" for SixDigits.
+func FormatCode(code int, digits Digits, opts FormatOptions) string {
+	s := strconv.Itoa(code)
+	if width := digits.Length(); opts.Pad && len(s) < width {
+		s = strings.Repeat("0", width-len(s)) + s
+	}
+
+	if opts.GroupSize <= 0 || len(s) <= opts.GroupSize {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i += opts.GroupSize {
+		if i > 0 {
+			b.WriteString(opts.Separator)
+		}
+
+		end := i + opts.GroupSize
+		if end > len(s) {
+			end = len(s)
+		}
+		b.WriteString(s[i:end])
+	}
+
+	return b.String()
+}
+
+// HOTPCodeString is HOTPCode with the code zero padded to the width implied by digits.
+func HOTPCodeString(hashProvider func() hash.Hash, key []byte, digits Digits, value int64) string {
+	return FormatCode(HOTPCode(hashProvider, key, digits, value), digits, FormatOptions{Pad: true})
+}
+
+// TOTPCodeString is TOTPCode with the code zero padded to the width implied by digits.
+func TOTPCodeString(hashProvider func() hash.Hash, key []byte, digits Digits, stepSizeSeconds int, t time.Time) string {
+	return FormatCode(TOTPCode(hashProvider, key, digits, stepSizeSeconds, t), digits, FormatOptions{Pad: true})
+}
@@ -0,0 +1,83 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestFormatCode(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Code   int
+		Digits Digits
+		Opts   FormatOptions
+		Output string
+	}{
+		{"Unpadded", 81804, SixDigits, FormatOptions{}, "81804"},
+		{"Padded", 81804, SixDigits, FormatOptions{Pad: true}, "081804"},
+		{"Padded Zero", 0, SixDigits, FormatOptions{Pad: true}, "000000"},
+		{"Padded Full Width", 123456, SixDigits, FormatOptions{Pad: true}, "123456"},
+		{"Padded Seven", 81804, SevenDigits, FormatOptions{Pad: true}, "0081804"},
+		{"Padded Eight", 7081804, EightDigits, FormatOptions{Pad: true}, "07081804"},
+		{"Grouped", 81804, SixDigits, FormatOptions{Pad: true, GroupSize: 3, Separator: " "}, "081 804"},
+		{"Grouped Non-Breaking", 81804, SixDigits, FormatOptions{Pad: true, GroupSize: 3, Separator: "\u00a0"}, "081\u00a0804"},
+		{"Grouped Uneven", 7081804, SevenDigits, FormatOptions{Pad: true, GroupSize: 3, Separator: "-"}, "708-180-4"},
+		{"Grouped Eight", 7081804, EightDigits, FormatOptions{Pad: true, GroupSize: 4, Separator: " "}, "0708 1804"},
+		{"Grouped Unpadded", 81804, SixDigits, FormatOptions{GroupSize: 3, Separator: " "}, "818 04"},
+		{"Group Larger Than Code", 81804, SixDigits, FormatOptions{Pad: true, GroupSize: 6, Separator: " "}, "081804"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			output := FormatCode(test.Code, test.Digits, test.Opts)
+			if output != test.Output {
+				t.Errorf("Output did not match. Expected %q and got %q.\n", test.Output, output)
+			}
+		})
+	}
+}
+
+func TestHOTPCodeString(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Value  int64
+		Digits Digits
+		Code   string
+	}{
+		{0, SixDigits, "755224"},
+		{1, SixDigits, "287082"},
+		{0x23523EC, SixDigits, "081804"},
+		{0x23523EC, SevenDigits, "7081804"},
+		{0x23523EC, EightDigits, "07081804"},
+	}
+
+	for _, test := range tests {
+		c := HOTPCodeString(sha1.New, key, test.Digits, test.Value)
+		if c != test.Code {
+			t.Errorf("Code did not match for %d. Expected %s but got %s\n", test.Value, test.Code, c)
+		}
+	}
+}
+
+func TestTOTPCodeString(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Digits Digits
+		Code   string
+	}{
+		{SixDigits, "081804"},
+		{SevenDigits, "7081804"},
+		{EightDigits, "07081804"},
+	}
+
+	for _, test := range tests {
+		c := TOTPCodeString(sha1.New, key, test.Digits, DefaultStepSizeSeconds, now)
+		if c != test.Code {
+			t.Errorf("Code did not match for %d. Expected %s but got %s\n", test.Digits, test.Code, c)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package otp
+
+import (
+	"time"
+)
+
+// Generator generates TOTP codes for a Config, giving call sites such as g.Now() in
+// place of passing every parameter to TOTPCode. It is the generating counterpart of
+// Verifier. Clock provides the time for Now and defaults to SystemClock.
+type Generator struct {
+	Config Config
+	Clock  Clock
+}
+
+// Now returns the code at the current time of the generator's Clock.
+func (g *Generator) Now() int {
+	return g.At(g.clock().Now())
+}
+
+// At returns the code for t, the same code as TOTPCode with the Config's parameters.
+func (g *Generator) At(t time.Time) int {
+	return g.Config.Code(t)
+}
+
+// String returns the code for t zero padded to the width of the Config's Digits, such as
+// "081804".
+func (g *Generator) String(t time.Time) string {
+	return FormatCode(g.At(t), g.Config.digits(), FormatOptions{Pad: true})
+}
+
+func (g *Generator) clock() Clock {
+	if g.Clock == nil {
+		return SystemClock{}
+	}
+	return g.Clock
+}
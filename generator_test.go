@@ -0,0 +1,69 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"testing"
+	"time"
+)
+
+func TestGenerator(t *testing.T) {
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name   string
+		Config Config
+		Code   int
+		String string
+	}{
+		{"Defaults", Config{Key: key}, 81804, "081804"},
+		{"Eight Digits", Config{Key: key, Digits: EightDigits}, 7081804, "07081804"},
+		{"SHA256", Config{Key: []byte("12345678901234567890123456789012"), HashProvider: sha256.New, Digits: EightDigits}, 68084774, "68084774"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			g := &Generator{Config: test.Config, Clock: NewFakeClock(testTime)}
+
+			if code := g.At(testTime); code != test.Code {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", test.Code, code)
+			}
+			if code := g.Now(); code != test.Code {
+				t.Errorf("Now did not match. Expected %d and got %d.\n", test.Code, code)
+			}
+			if s := g.String(testTime); s != test.String {
+				t.Errorf("String did not match. Expected %s and got %s.\n", test.String, s)
+			}
+		})
+	}
+}
+
+func TestGeneratorMatchesTOTPCode(t *testing.T) {
+	key := []byte("12345678901234567890")
+	g := &Generator{Config: Config{Key: key, HashProvider: sha1.New, Digits: SixDigits, StepSizeSeconds: 45}}
+
+	for _, seconds := range []int64{0, 44, 45, 59, 1111111109, 20000000000} {
+		now := time.Unix(seconds, 0)
+		if expected, code := TOTPCode(sha1.New, key, SixDigits, 45, now), g.At(now); code != expected {
+			t.Errorf("Code at %d did not match. Expected %d and got %d.\n", seconds, expected, code)
+		}
+	}
+}
+
+func TestGeneratorSystemClock(t *testing.T) {
+	g := &Generator{Config: Config{Key: []byte("12345678901234567890")}}
+
+	// retry in case the step changes between the two calls
+	for i := 0; i < 3; i++ {
+		before := time.Now()
+		code := g.Now()
+		if TimeStep(DefaultStepSizeSeconds, before) == TimeStep(DefaultStepSizeSeconds, time.Now()) {
+			if expected := g.At(before); code != expected {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", expected, code)
+			}
+			return
+		}
+	}
+	t.Error("Time step changed on every attempt")
+}
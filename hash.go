@@ -0,0 +1,99 @@
+package otp
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownHashProvider is returned by HashName for a hash provider that isn't registered.
+var ErrUnknownHashProvider = errors.New("otp: unknown hash provider")
+
+// namedHash is a hash algorithm registered by name.
+type namedHash struct {
+	name         string
+	hashProvider func() hash.Hash
+}
+
+// hashRegistry holds the algorithms supported by name, as used by otpauth URIs, in
+// registration order.
+var (
+	hashRegistryMu sync.RWMutex
+	hashRegistry   = []namedHash{
+		{"SHA1", sha1.New},
+		{"SHA256", sha256.New},
+		{"SHA512", sha512.New},
+	}
+)
+
+// RegisterHash makes hashProvider available by name to HashProviderByName, HashName and
+// the parsing and generation of otpauth URIs. SHA1, SHA256 and SHA512 are registered by
+// default. Registering a name again, ignoring case, replaces its provider.
+// An error is returned if name is empty, hashProvider is nil or its digest is shorter than
+// the 20 bytes dynamic truncation reads. It is safe for concurrent use.
+func RegisterHash(name string, hashProvider func() hash.Hash) error {
+	if name == "" {
+		return errors.New("otp: hash name is empty")
+	}
+	if hashProvider == nil {
+		return ErrNilHashProvider
+	}
+	if size := hashProvider().Size(); size < 20 {
+		return fmt.Errorf("otp: digest of %s is %d bytes, shorter than 20", name, size)
+	}
+
+	hashRegistryMu.Lock()
+	defer hashRegistryMu.Unlock()
+
+	for i, algorithm := range hashRegistry {
+		if strings.EqualFold(algorithm.name, name) {
+			hashRegistry[i] = namedHash{name, hashProvider}
+			return nil
+		}
+	}
+	hashRegistry = append(hashRegistry, namedHash{name, hashProvider})
+	return nil
+}
+
+// registeredHashes returns a snapshot of the registered algorithms.
+func registeredHashes() []namedHash {
+	hashRegistryMu.RLock()
+	defer hashRegistryMu.RUnlock()
+
+	return append([]namedHash(nil), hashRegistry...)
+}
+
+// HashProviderByName returns the hash provider registered for an algorithm name such as
+// "SHA256", ignoring case. An error is returned for an unknown name.
+func HashProviderByName(name string) (func() hash.Hash, error) {
+	for _, algorithm := range registeredHashes() {
+		if strings.EqualFold(algorithm.name, name) {
+			return algorithm.hashProvider, nil
+		}
+	}
+	return nil, fmt.Errorf("otp: unsupported algorithm %q", name)
+}
+
+// HashName returns the registered name of hashProvider, the inverse of HashProviderByName.
+// Functions can't be compared so algorithms are identified by their digest of an empty
+// input, which also matches providers wrapping a registered one.
+// ErrUnknownHashProvider is returned if hashProvider is nil or not registered.
+func HashName(hashProvider func() hash.Hash) (string, error) {
+	if hashProvider == nil {
+		return "", ErrUnknownHashProvider
+	}
+
+	digest := hashProvider().Sum(nil)
+	for _, algorithm := range registeredHashes() {
+		if bytes.Equal(algorithm.hashProvider().Sum(nil), digest) {
+			return algorithm.name, nil
+		}
+	}
+	return "", ErrUnknownHashProvider
+}
@@ -0,0 +1,177 @@
+package otp
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"sync"
+	"testing"
+)
+
+func TestHashProviderByName(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Algorithm string
+		Digest    func() hash.Hash
+		Err       string
+	}{
+		{"SHA1", "SHA1", sha1.New, ""},
+		{"Lowercase", "sha256", sha256.New, ""},
+		{"Mixed Case", "Sha512", sha512.New, ""},
+		{"Unknown", "MD5", nil, `otp: unsupported algorithm "MD5"`},
+		{"Empty", "", nil, `otp: unsupported algorithm ""`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hashProvider, err := HashProviderByName(test.Algorithm)
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(hashProvider().Sum(nil)) != string(test.Digest().Sum(nil)) {
+				t.Errorf("Hash provider did not match for %s", test.Algorithm)
+			}
+		})
+	}
+}
+
+func TestHashName(t *testing.T) {
+	tests := []struct {
+		Name         string
+		HashProvider func() hash.Hash
+		Algorithm    string
+		Err          error
+	}{
+		{"SHA1", sha1.New, "SHA1", nil},
+		{"SHA256", sha256.New, "SHA256", nil},
+		{"SHA512", sha512.New, "SHA512", nil},
+		{"Wrapped SHA256", func() hash.Hash { return sha256.New() }, "SHA256", nil},
+		{"MD5", md5.New, "", ErrUnknownHashProvider},
+		{"Nil", nil, "", ErrUnknownHashProvider},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			algorithm, err := HashName(test.HashProvider)
+			if algorithm != test.Algorithm || err != test.Err {
+				t.Errorf("Algorithm did not match. Expected %s, %v and got %s, %v.\n", test.Algorithm, test.Err, algorithm, err)
+			}
+			if err != nil {
+				return
+			}
+
+			hashProvider, err := HashProviderByName(algorithm)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if name, _ := HashName(hashProvider); name != algorithm {
+				t.Errorf("Round trip did not match. Expected %s and got %s.\n", algorithm, name)
+			}
+		})
+	}
+}
+
+// saveHashRegistry returns a func restoring the registry, undoing registrations made by
+// a test.
+func saveHashRegistry() func() {
+	registry := registeredHashes()
+	return func() {
+		hashRegistryMu.Lock()
+		defer hashRegistryMu.Unlock()
+		hashRegistry = registry
+	}
+}
+
+func TestRegisterHash(t *testing.T) {
+	defer saveHashRegistry()()
+
+	if _, err := HashProviderByName("SHA384"); err == nil {
+		t.Fatal("SHA384 was registered by default")
+	}
+
+	if err := RegisterHash("SHA384", sha512.New384); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	hashProvider, err := HashProviderByName("sha384")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name, err := HashName(hashProvider); name != "SHA384" || err != nil {
+		t.Errorf("Name did not match. Expected SHA384 and got %s, %v.\n", name, err)
+	}
+
+	validator, _, _, err := ParseURI("otpauth://totp/alice?secret=GEZDGNBVGY3TQOJQ&algorithm=SHA384")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name, _ := HashName(validator.HashProvider); name != "SHA384" {
+		t.Errorf("URI algorithm did not match. Expected SHA384 and got %s.\n", name)
+	}
+
+	// replacing a registration keeps the name as registered again
+	if err := RegisterHash("sha256", func() hash.Hash { return sha256.New() }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name, _ := HashName(sha256.New); name != "sha256" {
+		t.Errorf("Replaced name did not match. Expected sha256 and got %s.\n", name)
+	}
+	if count := len(registeredHashes()); count != 4 {
+		t.Errorf("Registry size did not match. Expected 4 and got %d.\n", count)
+	}
+}
+
+func TestRegisterHashConcurrent(t *testing.T) {
+	defer saveHashRegistry()()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterHash("SHA384", sha512.New384)
+		}()
+		go func() {
+			defer wg.Done()
+			HashName(sha512.New)
+		}()
+	}
+	wg.Wait()
+
+	if _, err := HashProviderByName("SHA384"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestRegisterHashInvalid(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Algorithm    string
+		HashProvider func() hash.Hash
+	}{
+		{"Empty Name", "", sha512.New384},
+		{"Nil Provider", "SHA384", nil},
+		{"Short Digest", "MD5", md5.New},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			defer saveHashRegistry()()
+
+			if err := RegisterHash(test.Algorithm, test.HashProvider); err == nil {
+				t.Error("Expected an error")
+			}
+			if count := len(registeredHashes()); count != 3 {
+				t.Errorf("Registry size did not match. Expected 3 and got %d.\n", count)
+			}
+		})
+	}
+}
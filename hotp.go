@@ -0,0 +1,113 @@
+package otp
+
+import (
+	"errors"
+	"hash"
+	"math"
+)
+
+// ErrCounterOverflow is returned by HOTPValidator.ValidateErr when the look-ahead would
+// advance the counter past math.MaxInt64.
+var ErrCounterOverflow = errors.New("otp: HOTP counter would overflow")
+
+// HOTPValidator assists in validating a provided HOTP code.
+// Counter is the next counter value expected from the token. LookAhead is the number of
+// counters after Counter that are also accepted to resynchronize with a token whose
+// counter has advanced without a successful validation, as described in RFC 4226
+// section 7.4. InitialCounter is the counter a token is manufactured with, which is
+// expected until a code has been accepted when it is ahead of Counter.
+type HOTPValidator struct {
+	Key            []byte
+	HashProvider   func() hash.Hash
+	Digits         Digits
+	Counter        int64
+	LookAhead      int
+	InitialCounter int64
+}
+
+// Validate returns a bool indicating if code is valid for a counter from the expected
+// counter, the later of Counter and InitialCounter, to that counter plus LookAhead. On
+// success the matched counter + 1 is returned which should be stored as Counter so the
+// code can't be reused. Otherwise Counter is returned unchanged.
+// Every counter in the look-ahead is compared in constant time and the earliest match is
+// returned. Every code is rejected if ValidateErr would return an error, which can be used
+// to tell a validator that can't be used from a wrong code.
+func (hv *HOTPValidator) Validate(code int) (bool, int64) {
+	ok, counter, _ := hv.ValidateErr(code)
+	return ok, counter
+}
+
+// ValidateErr is Validate returning an error when the validator can't validate codes.
+// ErrCounterOverflow is returned rather than wrapping negative when the matched counter + 1
+// could exceed math.MaxInt64, and the errors of HOTPCodeErr are returned for an empty Key.
+func (hv *HOTPValidator) ValidateErr(code int) (bool, int64, error) {
+	hashProvider := hv.HashProvider
+	if hashProvider == nil {
+		hashProvider = DefaultHashProvider
+	}
+	digits := hv.Digits
+	if digits == 0 {
+		digits = DefaultDigits
+	}
+
+	lookAhead := int64(0)
+	if hv.LookAhead > 0 {
+		lookAhead = int64(hv.LookAhead)
+	}
+	start := hv.expectedCounter()
+	// leave room to return the matched counter + 1
+	if lookAhead >= HOTPRemainingUses(start) {
+		return false, hv.Counter, ErrCounterOverflow
+	}
+
+	g, err := newHOTPGenerator(hashProvider, hv.Key, digits)
+	if err != nil {
+		return false, hv.Counter, err
+	}
+
+	matched := 0
+	next := hv.Counter
+	for i := int64(0); i <= lookAhead; i++ {
+		counter := start + i
+		isMatch := codesEqual(g.code(counter), code)
+
+		// subtle.ConstantTimeSelect is limited to int, which can't hold a counter on 32
+		// bit platforms
+		mask := -int64(isMatch &^ matched)
+		next = next&^mask | (counter+1)&mask
+		matched |= isMatch
+	}
+
+	return matched == 1, next, nil
+}
+
+// expectedCounter returns the next counter expected from the token.
+func (hv *HOTPValidator) expectedCounter() int64 {
+	if hv.InitialCounter > hv.Counter {
+		return hv.InitialCounter
+	}
+	return hv.Counter
+}
+
+// KeyURI returns the provisioning URI for the validator's token, an hotp URI whose
+// counter parameter is the next counter expected so the token and validator agree.
+func (hv *HOTPValidator) KeyURI(issuer, account string) KeyURI {
+	return KeyURI{
+		Issuer:       issuer,
+		Account:      account,
+		Key:          hv.Key,
+		Digits:       hv.Digits,
+		HashProvider: hv.HashProvider,
+		HOTP:         true,
+		Counter:      hv.expectedCounter(),
+	}
+}
+
+// HOTPRemainingUses returns how many times an HOTP counter can be incremented from
+// currentCounter before it would overflow an int64. Negative counters are treated as 0.
+func HOTPRemainingUses(currentCounter int64) int64 {
+	if currentCounter < 0 {
+		return math.MaxInt64
+	}
+	return math.MaxInt64 - currentCounter
+}
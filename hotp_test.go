@@ -0,0 +1,219 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"hash"
+	"math"
+	"testing"
+)
+
+func TestHOTPRemainingUses(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Counter   int64
+		Remaining int64
+	}{
+		{"Zero", 0, math.MaxInt64},
+		{"One", 1, math.MaxInt64 - 1},
+		{"Negative", -5, math.MaxInt64},
+		{"Near Max", math.MaxInt64 - 10, 10},
+		{"Max", math.MaxInt64, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			remaining := HOTPRemainingUses(test.Counter)
+			if remaining != test.Remaining {
+				t.Errorf("Remaining did not match. Expected %d and got %d.\n", test.Remaining, remaining)
+			}
+		})
+	}
+}
+
+func TestHOTPValidator(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name      string
+		Counter   int64
+		LookAhead int
+		Code      int
+		OK        bool
+		Next      int64
+	}{
+		{"Current", 0, 0, rfc4226Vectors[0], true, 1},
+		{"Next Counter", 1, 0, rfc4226Vectors[1], true, 2},
+		{"Previous Counter", 1, 5, rfc4226Vectors[0], false, 1},
+		{"Ahead Without Look Ahead", 0, 0, rfc4226Vectors[1], false, 0},
+		{"Resynchronize", 0, 5, rfc4226Vectors[5], true, 6},
+		{"Look Ahead Limit", 2, 7, rfc4226Vectors[9], true, 10},
+		{"Beyond Look Ahead", 2, 6, rfc4226Vectors[9], false, 2},
+		{"Negative Look Ahead", 3, -1, rfc4226Vectors[3], true, 4},
+		{"Wrong Code", 0, 9, 123456, false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := HOTPValidator{
+				Key:       key,
+				Counter:   test.Counter,
+				LookAhead: test.LookAhead,
+			}
+
+			ok, next := validator.Validate(test.Code)
+			if ok != test.OK {
+				t.Errorf("Validation did not match. Expected %t and got %t.\n", test.OK, ok)
+			}
+			if next != test.Next {
+				t.Errorf("Counter did not match. Expected %d and got %d.\n", test.Next, next)
+			}
+		})
+	}
+}
+
+func TestHOTPValidatorErr(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name      string
+		Key       []byte
+		Counter   int64
+		LookAhead int
+		Code      int64
+		OK        bool
+		Next      int64
+		Err       error
+	}{
+		{"Last Usable", key, math.MaxInt64 - 11, 10, math.MaxInt64 - 1, true, math.MaxInt64, nil},
+		{"Look Ahead Overflows", key, math.MaxInt64 - 10, 10, math.MaxInt64 - 1, false, math.MaxInt64 - 10, ErrCounterOverflow},
+		{"At Max", key, math.MaxInt64, 0, math.MaxInt64, false, math.MaxInt64, ErrCounterOverflow},
+		{"Negative Counter", key, -1, 1, 0, true, 1, nil},
+		{"Empty Key", nil, 0, 0, 0, false, 0, ErrEmptyKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := HOTPValidator{
+				Key:       test.Key,
+				Counter:   test.Counter,
+				LookAhead: test.LookAhead,
+			}
+			code := HOTPCode(sha1.New, key, SixDigits, test.Code)
+
+			ok, next, err := validator.ValidateErr(code)
+			if ok != test.OK {
+				t.Errorf("Validation did not match. Expected %t and got %t.\n", test.OK, ok)
+			}
+			if next != test.Next {
+				t.Errorf("Counter did not match. Expected %d and got %d.\n", test.Next, next)
+			}
+			if err != test.Err {
+				t.Errorf("Error did not match. Expected %v and got %v.\n", test.Err, err)
+			}
+
+			if ok, next := validator.Validate(code); ok != test.OK || next != test.Next {
+				t.Errorf("Validate did not match. Expected %t, %d and got %t, %d.\n", test.OK, test.Next, ok, next)
+			}
+		})
+	}
+}
+
+func TestHOTPValidatorScansLookAhead(t *testing.T) {
+	tests := []struct {
+		Name string
+		Code int
+		OK   bool
+		Next int64
+	}{
+		{"Match First Counter", rfc4226Vectors[0], true, 1},
+		{"Match Last Counter", rfc4226Vectors[9], true, 10},
+		{"No Match", 123456, false, 0},
+	}
+
+	counts := make(map[int]bool)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sums := 0
+			validator := HOTPValidator{
+				Key: []byte("12345678901234567890"),
+				HashProvider: func() hash.Hash {
+					return summingHash{sha1.New(), &sums}
+				},
+				LookAhead: 9,
+			}
+
+			ok, next := validator.Validate(test.Code)
+			if ok != test.OK || next != test.Next {
+				t.Errorf("Validation did not match. Expected %t, %d and got %t, %d.\n", test.OK, test.Next, ok, next)
+			}
+			counts[sums] = true
+		})
+	}
+
+	if len(counts) != 1 {
+		t.Errorf("Validation cost varied: %v", counts)
+	}
+}
+
+func TestHOTPValidatorInitialCounter(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name           string
+		Counter        int64
+		InitialCounter int64
+		Code           int
+		OK             bool
+		Next           int64
+	}{
+		{"Initial Counter", 0, 5, rfc4226Vectors[5], true, 6},
+		{"Before Initial Counter", 0, 5, rfc4226Vectors[0], false, 0},
+		{"Look Ahead From Initial Counter", 0, 5, rfc4226Vectors[7], true, 8},
+		{"Counter Past Initial Counter", 7, 5, rfc4226Vectors[7], true, 8},
+		{"Initial Counter Already Used", 7, 5, rfc4226Vectors[5], false, 7},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := HOTPValidator{
+				Key:            key,
+				Counter:        test.Counter,
+				LookAhead:      2,
+				InitialCounter: test.InitialCounter,
+			}
+
+			ok, next := validator.Validate(test.Code)
+			if ok != test.OK {
+				t.Errorf("Validation did not match. Expected %t and got %t.\n", test.OK, ok)
+			}
+			if next != test.Next {
+				t.Errorf("Counter did not match. Expected %d and got %d.\n", test.Next, next)
+			}
+		})
+	}
+}
+
+func TestHOTPValidatorKeyURI(t *testing.T) {
+	validator := HOTPValidator{
+		Key:            []byte("12345678901234567890"),
+		Digits:         EightDigits,
+		InitialCounter: 1000,
+	}
+
+	uri := validator.KeyURI("Example", "alice").String()
+	expected := "otpauth://hotp/Example:alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&issuer=Example&algorithm=SHA1&digits=8&counter=1000"
+	if uri != expected {
+		t.Errorf("URI did not match. Expected %s and got %s.\n", expected, uri)
+	}
+
+	k, err := ParseKeyURI(uri)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !k.HOTP || k.Counter != 1000 {
+		t.Errorf("Counter did not match. Expected hotp counter 1000 and got %t, %d.\n", k.HOTP, k.Counter)
+	}
+	if k.Digits != EightDigits {
+		t.Errorf("Digits did not match. Expected %d and got %d.\n", EightDigits, k.Digits)
+	}
+}
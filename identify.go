@@ -0,0 +1,49 @@
+package otp
+
+import (
+	"time"
+)
+
+// identifyOffsets are the time step offsets from now IdentifyToken searches, in order of
+// decreasing confidence.
+var identifyOffsets = []int{0, -1, 1, -2, 2}
+
+// Identification is the configuration of a token identified by IdentifyToken.
+type Identification struct {
+	// Config is the matching candidate with its Key set to the identified key.
+	Config Config
+	// Offset is the number of time steps the token's clock is ahead of now, negative when
+	// it is behind. An Offset of 0 is the most confident identification and the
+	// confidence drops as the offset grows.
+	Offset int
+}
+
+// IdentifyToken determines which of candidates an unknown token holding key uses by
+// checking code against each candidate's codes within two time steps of now. The Key of
+// each candidate is ignored in favour of key. The candidate matching at the smallest
+// offset is returned, preferring earlier candidates when several match at the same
+// offset, along with true. false is returned if no candidate matches. With short codes a
+// wrong candidate can match by chance so an identification should be confirmed with
+// another code before it is relied on.
+func IdentifyToken(key []byte, candidates []Config, now time.Time, code int) (*Identification, bool) {
+	generators := make([]*hotpGenerator, len(candidates))
+	for i, candidate := range candidates {
+		g, err := newHOTPGenerator(candidate.hashProvider(), key, candidate.digits())
+		if err != nil {
+			return nil, false
+		}
+		generators[i] = g
+	}
+
+	for _, offset := range identifyOffsets {
+		for i, candidate := range candidates {
+			step := TimeStep(candidate.stepSizeSeconds(), now) + offset
+			if codesEqual(generators[i].code(int64(step)), code) == 1 {
+				candidate.Key = key
+				return &Identification{Config: candidate, Offset: offset}, true
+			}
+		}
+	}
+
+	return nil, false
+}
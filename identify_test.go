@@ -0,0 +1,68 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+	"time"
+)
+
+func TestIdentifyToken(t *testing.T) {
+	key := []byte("12345678901234567890")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	candidates := []Config{
+		{HashProvider: sha1.New, Digits: EightDigits},
+		{HashProvider: sha256.New, Digits: EightDigits},
+		{HashProvider: sha512.New, Digits: EightDigits},
+		{HashProvider: sha1.New, Digits: EightDigits, StepSizeSeconds: 60},
+	}
+
+	tests := []struct {
+		Name   string
+		Code   int
+		Match  bool
+		Index  int
+		Offset int
+	}{
+		{"SHA1", 7081804, true, 0, 0},
+		{"SHA1 Behind", 89731029, true, 0, -1},
+		{"SHA1 Ahead", 14050471, true, 0, 1},
+		{"SHA256", HOTPCode(sha256.New, key, EightDigits, 0x23523EC), true, 1, 0},
+		{"SHA512 Two Steps Ahead", HOTPCode(sha512.New, key, EightDigits, 0x23523EE), true, 2, 2},
+		{"Minute Step", HOTPCode(sha1.New, key, EightDigits, 0x23523EC/2), true, 3, 0},
+		{"Three Steps Ahead", HOTPCode(sha1.New, key, EightDigits, 0x23523EF), false, 0, 0},
+		{"No Match", 7081803, false, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			identification, ok := IdentifyToken(key, candidates, testTime, test.Code)
+			if ok != test.Match {
+				t.Fatalf("Match did not match. Expected %t and got %t.\n", test.Match, ok)
+			}
+			if !ok {
+				return
+			}
+
+			expected := candidates[test.Index]
+			if identification.Config.StepSizeSeconds != expected.StepSizeSeconds ||
+				identification.Config.HashProvider().Size() != expected.HashProvider().Size() {
+				t.Errorf("Config did not match. Expected candidate %d and got %+v.\n", test.Index, identification.Config)
+			}
+			if string(identification.Config.Key) != string(key) {
+				t.Errorf("Key did not match. Got %q.\n", identification.Config.Key)
+			}
+			if identification.Offset != test.Offset {
+				t.Errorf("Offset did not match. Expected %d and got %d.\n", test.Offset, identification.Offset)
+			}
+		})
+	}
+}
+
+func TestIdentifyTokenEmptyKey(t *testing.T) {
+	if _, ok := IdentifyToken(nil, []Config{{}}, time.Now(), 0); ok {
+		t.Error("Token with an empty key was identified")
+	}
+}
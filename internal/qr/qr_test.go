@@ -0,0 +1,128 @@
+package qr
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	// verified to decode with an independent QR code reader
+	expected := []string{
+		"#######..#..##..#.#######",
+		"#.....#.##..#.###.#.....#",
+		"#.###.#..#...##.#.#.###.#",
+		"#.###.#..#.#####..#.###.#",
+		"#.###.#.#.#...#...#.###.#",
+		"#.....#..#...#....#.....#",
+		"#######.#.#.#.#.#.#######",
+		".........#...##.#........",
+		"#.#.#.#...##..###...#..#.",
+		"...###.####...##.###.#..#",
+		"#.#####...#.##...#.#..###",
+		"..#.#..#..##....#.#.#..#.",
+		"..##.##.##..#..####....##",
+		"...#.#.#.#.###....##.#..#",
+		"#..#..#..#.##.#.###..####",
+		".##.##.####.####.#.##...#",
+		"#.#.###..#.#..#.######.#.",
+		"........##.#..#.#...#..##",
+		"#######...#..#.##.#.#####",
+		"#.....#..#.#....#...#...#",
+		"#.###.#.###.#...######.#.",
+		"#.###.#...####.#....#..#.",
+		"#.###.#.#.###.###...#.#.#",
+		"#.....#...#.###.#.##...#.",
+		"#######.##.#..####.....##",
+	}
+
+	code, err := Encode([]byte("otpauth://totp/"), M)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if code.Size != len(expected) {
+		t.Fatalf("Size did not match. Expected %d and got %d.\n", len(expected), code.Size)
+	}
+
+	for y, row := range expected {
+		var b bytes.Buffer
+		for x := 0; x < code.Size; x++ {
+			if code.Black(x, y) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		if b.String() != row {
+			t.Errorf("Row %d did not match. Expected %s and got %s.\n", y, row, b.String())
+		}
+	}
+}
+
+func TestEncodeVersion(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Length int
+		Level  Level
+		Size   int
+	}{
+		{"Empty", 0, L, 21},
+		{"Version 1 Low Capacity", 17, L, 21},
+		{"Version 1 Low Overflow", 18, L, 25},
+		{"Version 1 High Capacity", 7, H, 21},
+		{"Version 1 High Overflow", 8, H, 25},
+		{"Version 7 Version Information", 154, L, 45},
+		{"Version 10 Count Width", 213, M, 57},
+		{"Version 40 Low Capacity", 2953, L, 177},
+		{"Version 40 High Capacity", 1273, H, 177},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code, err := Encode(make([]byte, test.Length), test.Level)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if code.Size != test.Size {
+				t.Errorf("Size did not match. Expected %d and got %d.\n", test.Size, code.Size)
+			}
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Length int
+		Level  Level
+		Err    error
+	}{
+		{"Too Long Low", 2954, L, ErrTooLong},
+		{"Too Long High", 1274, H, ErrTooLong},
+		{"Negative Level", 1, -1, ErrInvalidLevel},
+		{"Unknown Level", 1, H + 1, ErrInvalidLevel},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if _, err := Encode(make([]byte, test.Length), test.Level); err != test.Err {
+				t.Errorf("Error did not match. Expected %v and got %v.\n", test.Err, err)
+			}
+		})
+	}
+}
+
+func TestBlackOutsideSymbol(t *testing.T) {
+	code, err := Encode([]byte("otp"), L)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	for _, p := range [][2]int{{-1, 0}, {0, -1}, {code.Size, 0}, {0, code.Size}} {
+		if code.Black(p[0], p[1]) {
+			t.Errorf("Module %v outside the symbol was dark.\n", p)
+		}
+	}
+	if !code.Black(0, 0) {
+		t.Error("Finder pattern corner was light")
+	}
+}
@@ -0,0 +1,289 @@
+package qr
+
+// symbol is a symbol under construction. Function modules, the finder, timing and
+// alignment patterns and the format and version information, are excluded from data
+// placement and masking.
+type symbol struct {
+	size     int
+	modules  []bool
+	function []bool
+}
+
+func newSymbol(version int) *symbol {
+	size := version*4 + 17
+	return &symbol{
+		size:     size,
+		modules:  make([]bool, size*size),
+		function: make([]bool, size*size),
+	}
+}
+
+func (s *symbol) setFunction(x, y int, dark bool) {
+	s.modules[y*s.size+x] = dark
+	s.function[y*s.size+x] = true
+}
+
+// drawFunctionPatterns draws everything but the data, reserving the format information
+// area which is drawn once the mask is chosen.
+func (s *symbol) drawFunctionPatterns(level Level) {
+	for i := 0; i < s.size; i++ {
+		s.setFunction(6, i, i%2 == 0)
+		s.setFunction(i, 6, i%2 == 0)
+	}
+
+	s.drawFinder(3, 3)
+	s.drawFinder(s.size-4, 3)
+	s.drawFinder(3, s.size-4)
+
+	positions := s.alignmentPositions()
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// alignment patterns would overlap the finder patterns in these corners
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			s.drawAlignment(x, y)
+		}
+	}
+
+	s.drawFormat(level, 0)
+	s.drawVersion()
+}
+
+// drawFinder draws a finder pattern and its separator centred on x, y.
+func (s *symbol) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= s.size || yy >= s.size {
+				continue
+			}
+			dist := maxInt(abs(dx), abs(dy))
+			s.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignment draws an alignment pattern centred on x, y.
+func (s *symbol) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			s.setFunction(x+dx, y+dy, maxInt(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPositions returns the row and column coordinates of the alignment pattern
+// centres in ascending order.
+func (s *symbol) alignmentPositions() []int {
+	version := (s.size - 17) / 4
+	if version == 1 {
+		return nil
+	}
+
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+
+	positions := make([]int, numAlign)
+	positions[0] = 6
+	for i, pos := numAlign-1, s.size-7; i > 0; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// formatLevelBits are the format information bits of each level.
+var formatLevelBits = [4]int{L: 1, M: 0, Q: 3, H: 2}
+
+// drawFormat draws both copies of the format information for level and mask.
+func (s *symbol) drawFormat(level Level, mask int) {
+	data := formatLevelBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	for i := 0; i <= 5; i++ {
+		s.setFunction(8, i, bit(bits, i))
+	}
+	s.setFunction(8, 7, bit(bits, 6))
+	s.setFunction(8, 8, bit(bits, 7))
+	s.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		s.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		s.setFunction(s.size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		s.setFunction(8, s.size-15+i, bit(bits, i))
+	}
+	// the dark module
+	s.setFunction(8, s.size-8, true)
+}
+
+// drawVersion draws both copies of the version information, present from version 7.
+func (s *symbol) drawVersion() {
+	version := (s.size - 17) / 4
+	if version < 7 {
+		return
+	}
+
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		a, b := s.size-11+i%3, i/3
+		s.setFunction(a, b, bit(bits, i))
+		s.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords places data in the non-function modules in the zigzag order of the
+// specification, two columns at a time from the bottom right.
+func (s *symbol) drawCodewords(data []byte) {
+	i := 0
+	for right := s.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// skip the vertical timing pattern
+			right = 5
+		}
+		for vert := 0; vert < s.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = s.size - 1 - vert
+				}
+				if s.function[y*s.size+x] || i >= len(data)*8 {
+					continue
+				}
+				s.modules[y*s.size+x] = data[i/8]>>uint(7-i%8)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by mask.
+func (s *symbol) applyMask(mask int) {
+	for y := 0; y < s.size; y++ {
+		for x := 0; x < s.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !s.function[y*s.size+x] {
+				s.modules[y*s.size+x] = !s.modules[y*s.size+x]
+			}
+		}
+	}
+}
+
+// finderLike is the 1:1:3:1:1 finder pattern ratio followed by four light modules.
+var finderLike = []bool{true, false, true, true, true, false, true, false, false, false, false}
+
+// penalty scores the symbol using the four rules of the specification. The mask giving
+// the lowest score is used.
+func (s *symbol) penalty() int {
+	dark := func(x, y int) bool { return s.modules[y*s.size+x] }
+	result := 0
+
+	for _, vertical := range []bool{false, true} {
+		at := dark
+		if vertical {
+			at = func(x, y int) bool { return dark(y, x) }
+		}
+
+		for y := 0; y < s.size; y++ {
+			// runs of five or more modules of the same colour
+			run := 1
+			for x := 1; x <= s.size; x++ {
+				if x < s.size && at(x, y) == at(x-1, y) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					result += 3 + run - 5
+				}
+				run = 1
+			}
+
+			// patterns resembling a finder pattern, in either direction
+			for x := 0; x+len(finderLike) <= s.size; x++ {
+				forward, backward := true, true
+				for i, d := range finderLike {
+					forward = forward && at(x+i, y) == d
+					backward = backward && at(x+len(finderLike)-1-i, y) == d
+				}
+				if forward {
+					result += 40
+				}
+				if backward {
+					result += 40
+				}
+			}
+		}
+	}
+
+	// two by two blocks of the same colour
+	for y := 0; y+1 < s.size; y++ {
+		for x := 0; x+1 < s.size; x++ {
+			c := dark(x, y)
+			if c == dark(x+1, y) && c == dark(x, y+1) && c == dark(x+1, y+1) {
+				result += 3
+			}
+		}
+	}
+
+	// deviation of the proportion of dark modules from half
+	count := 0
+	for _, m := range s.modules {
+		if m {
+			count++
+		}
+	}
+	total := s.size * s.size
+	result += abs(count*20-total*10) / total * 10
+
+	return result
+}
+
+func bit(v, i int) bool {
+	return v>>uint(i)&1 == 1
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,161 @@
+package otp
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// validatorJSON is the JSON form of a TOTPValidator. Keys are unpadded base32, the
+// algorithm is its registered name, digits is the code length and durations are in
+// nanoseconds as for time.Duration.
+type validatorJSON struct {
+	Key                 string        `json:"key"`
+	Algorithm           string        `json:"algorithm"`
+	Digits              int           `json:"digits"`
+	StepSizeSeconds     int           `json:"stepSizeSeconds,omitempty"`
+	StepSize            time.Duration `json:"stepSize,omitempty"`
+	Epoch               *time.Time    `json:"epoch,omitempty"`
+	PastTolerance       time.Duration `json:"pastTolerance,omitempty"`
+	FutureTolerance     time.Duration `json:"futureTolerance,omitempty"`
+	PastSteps           int           `json:"pastSteps,omitempty"`
+	FutureSteps         int           `json:"futureSteps,omitempty"`
+	MaxWindowSteps      int           `json:"maxWindowSteps,omitempty"`
+	LastT               int           `json:"lastT,omitempty"`
+	AcceptOffsets       []int         `json:"acceptOffsets,omitempty"`
+	CounterKey          string        `json:"counterKey,omitempty"`
+	FixedScanSteps      int           `json:"fixedScanSteps,omitempty"`
+	EnrolledAt          *time.Time    `json:"enrolledAt,omitempty"`
+	EnrollmentAge       time.Duration `json:"enrollmentAge,omitempty"`
+	EnrollmentTolerance time.Duration `json:"enrollmentTolerance,omitempty"`
+	PreviousKey         string        `json:"previousKey,omitempty"`
+	RotatedAt           *time.Time    `json:"rotatedAt,omitempty"`
+	RotationGrace       time.Duration `json:"rotationGrace,omitempty"`
+	LastSuccess         *time.Time    `json:"lastSuccess,omitempty"`
+}
+
+// MarshalJSON encodes the validator's configuration and state for storage. Keys are
+// encoded as base32, the hash provider by its registered name and Digits by its length,
+// so a nil HashProvider is stored as the name of DefaultHashProvider and zero Digits as
+// the length of DefaultDigits. ReplayStore and Clock are not encoded. An error is returned
+// if HashProvider is not registered. MarshalJSON has a pointer receiver so a *TOTPValidator
+// must be marshaled.
+func (tc *TOTPValidator) MarshalJSON() ([]byte, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	algorithm, err := HashName(tc.hashProvider())
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(validatorJSON{
+		Key:                 secretEncoding.EncodeToString(tc.Key),
+		Algorithm:           algorithm,
+		Digits:              tc.digits().Length(),
+		StepSizeSeconds:     tc.StepSizeSeconds,
+		StepSize:            tc.StepSize,
+		Epoch:               optionalTime(tc.Epoch),
+		PastTolerance:       tc.PastTolerance,
+		FutureTolerance:     tc.FutureTolerance,
+		PastSteps:           tc.PastSteps,
+		FutureSteps:         tc.FutureSteps,
+		MaxWindowSteps:      tc.MaxWindowSteps,
+		LastT:               tc.LastT,
+		AcceptOffsets:       tc.AcceptOffsets,
+		CounterKey:          secretEncoding.EncodeToString(tc.CounterKey),
+		FixedScanSteps:      tc.FixedScanSteps,
+		EnrolledAt:          optionalTime(tc.EnrolledAt),
+		EnrollmentAge:       tc.EnrollmentAge,
+		EnrollmentTolerance: tc.EnrollmentTolerance,
+		PreviousKey:         secretEncoding.EncodeToString(tc.PreviousKey),
+		RotatedAt:           optionalTime(tc.RotatedAt),
+		RotationGrace:       tc.RotationGrace,
+		LastSuccess:         optionalTime(tc.LastSuccess),
+	})
+}
+
+// UnmarshalJSON decodes a validator encoded by MarshalJSON, resolving the algorithm name
+// with HashProviderByName. ReplayStore and Clock are left unchanged. An error is returned
+// for an invalid key, an unknown algorithm or an unsupported number of digits.
+func (tc *TOTPValidator) UnmarshalJSON(data []byte) error {
+	var v validatorJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	key, err := DecodeSecret(v.Key)
+	if err != nil {
+		return err
+	}
+	counterKey, err := decodeOptionalSecret(v.CounterKey)
+	if err != nil {
+		return err
+	}
+	previousKey, err := decodeOptionalSecret(v.PreviousKey)
+	if err != nil {
+		return err
+	}
+
+	hashProvider, err := HashProviderByName(v.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	digits, err := NewDigits(v.Digits)
+	if err != nil {
+		return err
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.Key = key
+	tc.HashProvider = hashProvider
+	tc.Digits = digits
+	tc.StepSizeSeconds = v.StepSizeSeconds
+	tc.StepSize = v.StepSize
+	tc.Epoch = requiredTime(v.Epoch)
+	tc.PastTolerance = v.PastTolerance
+	tc.FutureTolerance = v.FutureTolerance
+	tc.PastSteps = v.PastSteps
+	tc.FutureSteps = v.FutureSteps
+	tc.MaxWindowSteps = v.MaxWindowSteps
+	tc.LastT = v.LastT
+	tc.AcceptOffsets = v.AcceptOffsets
+	tc.CounterKey = counterKey
+	tc.FixedScanSteps = v.FixedScanSteps
+	tc.EnrolledAt = requiredTime(v.EnrolledAt)
+	tc.EnrollmentAge = v.EnrollmentAge
+	tc.EnrollmentTolerance = v.EnrollmentTolerance
+	tc.PreviousKey = previousKey
+	tc.RotatedAt = requiredTime(v.RotatedAt)
+	tc.RotationGrace = v.RotationGrace
+	tc.LastSuccess = requiredTime(v.LastSuccess)
+	tc.index = nil
+
+	return nil
+}
+
+// decodeOptionalSecret is DecodeSecret returning nil for an empty secret.
+func decodeOptionalSecret(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return DecodeSecret(s)
+}
+
+// optionalTime returns nil for the zero time so it is omitted from JSON.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// requiredTime is the inverse of optionalTime.
+func requiredTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,131 @@
+package otp
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidatorMarshalJSON(t *testing.T) {
+	validator := &TOTPValidator{
+		Key:           []byte("12345678901234567890"),
+		HashProvider:  sha256.New,
+		Digits:        EightDigits,
+		PastTolerance: 30 * time.Second,
+		LastT:         0x23523EC,
+	}
+
+	data, err := json.Marshal(validator)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	expected := `{"key":"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ","algorithm":"SHA256","digits":8,"pastTolerance":30000000000,"lastT":37037036}`
+	if string(data) != expected {
+		t.Errorf("JSON did not match. Expected %s and got %s.\n", expected, data)
+	}
+}
+
+func TestValidatorMarshalJSONDefaults(t *testing.T) {
+	data, err := json.Marshal(&TOTPValidator{Key: []byte("12345678901234567890")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	expected := `{"key":"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ","algorithm":"SHA1","digits":6}`
+	if string(data) != expected {
+		t.Errorf("JSON did not match. Expected %s and got %s.\n", expected, data)
+	}
+}
+
+func TestValidatorJSONRoundTrip(t *testing.T) {
+	enrolled := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	validator := &TOTPValidator{
+		Key:                 []byte("12345678901234567890"),
+		StepSizeSeconds:     60,
+		StepSize:            time.Minute,
+		Epoch:               time.Unix(30, 0).UTC(),
+		PastTolerance:       time.Minute,
+		FutureTolerance:     30 * time.Second,
+		PastSteps:           2,
+		FutureSteps:         1,
+		MaxWindowSteps:      5,
+		LastT:               18518000,
+		AcceptOffsets:       []int{-1, 0},
+		HashProvider:        sha1.New,
+		Digits:              EightDigits,
+		CounterKey:          []byte("counter key"),
+		FixedScanSteps:      4,
+		EnrolledAt:          enrolled,
+		EnrollmentAge:       time.Hour,
+		EnrollmentTolerance: 5 * time.Minute,
+		PreviousKey:         []byte("previous key"),
+		RotatedAt:           enrolled.Add(time.Minute),
+		RotationGrace:       time.Hour,
+		LastSuccess:         enrolled.Add(time.Second),
+	}
+
+	data, err := json.Marshal(validator)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	decoded := &TOTPValidator{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	if !reflect.DeepEqual(decoded.PreviousKey, validator.PreviousKey) {
+		t.Errorf("Previous key did not match. Expected %v and got %v.\n", validator.PreviousKey, decoded.PreviousKey)
+	}
+
+	// the hash provider can't be compared directly so compare the encodings
+	if encoded, _ := json.Marshal(decoded); string(encoded) != string(data) {
+		t.Errorf("Round trip did not match. Expected %s and got %s.\n", data, encoded)
+	}
+
+	code := TOTPCodeAt(sha1.New, validator.Key, EightDigits, 60, validator.Epoch, enrolled.Add(time.Hour))
+	if ok, _ := decoded.ValidateTOTPCode(enrolled.Add(time.Hour), code); !ok {
+		t.Error("Code did not match after round trip")
+	}
+}
+
+func TestValidatorUnmarshalJSONErrors(t *testing.T) {
+	tests := []struct {
+		Name string
+		JSON string
+	}{
+		{"Invalid JSON", `{"key":`},
+		{"Missing Key", `{"algorithm":"SHA1","digits":6}`},
+		{"Invalid Key", `{"key":"1234","algorithm":"SHA1","digits":6}`},
+		{"Invalid Counter Key", `{"key":"GEZDGNBV","algorithm":"SHA1","digits":6,"counterKey":"1"}`},
+		{"Unknown Algorithm", `{"key":"GEZDGNBV","algorithm":"MD5","digits":6}`},
+		{"Missing Digits", `{"key":"GEZDGNBV","algorithm":"SHA1"}`},
+		{"Too Many Digits", `{"key":"GEZDGNBV","algorithm":"SHA1","digits":11}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var validator TOTPValidator
+			if err := json.Unmarshal([]byte(test.JSON), &validator); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestValidatorMarshalJSONUnknownHash(t *testing.T) {
+	validator := &TOTPValidator{
+		Key:          []byte("12345678901234567890"),
+		HashProvider: md5.New,
+	}
+
+	if _, err := json.Marshal(validator); !errors.Is(err, ErrUnknownHashProvider) {
+		t.Errorf("Error did not match. Expected %v and got %v.\n", ErrUnknownHashProvider, err)
+	}
+}
@@ -0,0 +1,63 @@
+package otp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// keePassDefaultSettings are the TOTP settings KeePass assumes when none are stored.
+const keePassDefaultSettings = "30;6"
+
+// ParseKeePassTOTP parses the TOTP of a KeePass entry. seed is the "TOTP Seed" field,
+// either an otpauth:// URI, which is parsed by ParseKeyURI and takes precedence over
+// settings, or a base32 secret that may be grouped with spaces. settings is the
+// "TOTP Settings" field of the form "period;digits", such as "30;6", or "period;S" for a
+// Steam Guard key. Empty settings use the KeePass default of "30;6". The returned KeyURI
+// has no issuer or account as KeePass stores them in other fields.
+func ParseKeePassTOTP(seed string, settings string) (*KeyURI, error) {
+	seed = strings.TrimSpace(seed)
+	if hasPrefixFold(seed, uriScheme) {
+		return ParseKeyURI(seed)
+	}
+
+	key, err := DecodeSecret(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	settings = strings.TrimSpace(settings)
+	if settings == "" {
+		settings = keePassDefaultSettings
+	}
+	parts := strings.Split(settings, ";")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("otp: invalid KeePass TOTP settings %q", settings)
+	}
+
+	period, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || period <= 0 {
+		return nil, fmt.Errorf("otp: invalid KeePass TOTP period %q", parts[0])
+	}
+
+	k := &KeyURI{
+		Key:             key,
+		HashProvider:    DefaultHashProvider,
+		StepSizeSeconds: period,
+	}
+
+	if length := strings.TrimSpace(parts[1]); strings.EqualFold(length, "S") {
+		k.Steam = true
+		k.Digits, _ = NewDigits(steamCodeLength)
+	} else {
+		n, err := strconv.Atoi(length)
+		if err != nil {
+			return nil, fmt.Errorf("otp: invalid KeePass TOTP digits %q", parts[1])
+		}
+		if k.Digits, err = NewDigits(n); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
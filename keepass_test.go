@@ -0,0 +1,63 @@
+package otp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseKeePassTOTP(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name     string
+		Seed     string
+		Settings string
+		Digits   Digits
+		StepSize int
+		Steam    bool
+		Err      string
+	}{
+		{"Default Settings", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "", SixDigits, 30, false, ""},
+		{"Settings", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "60;8", EightDigits, 60, false, ""},
+		{"Spaced Seed", "gezd gnbv gy3t qojq gezd gnbv gy3t qojq", "30;6", SixDigits, 30, false, ""},
+		{"Steam", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "30;S", Digits(100000), 30, true, ""},
+		{"URI", "otpauth://totp/Example:alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&digits=7&period=45", "30;6",
+			SevenDigits, 45, false, ""},
+		{"Steam URI", "otpauth://totp/Steam:alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&digits=5&encoder=steam", "",
+			Digits(100000), 30, true, ""},
+		{"Bad Seed", "1NVALID", "30;6", 0, 0, false, `otp: invalid character '1' at position 0 in secret`},
+		{"Bad Settings", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "30", 0, 0, false, `otp: invalid KeePass TOTP settings "30"`},
+		{"Bad Period", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "0;6", 0, 0, false, `otp: invalid KeePass TOTP period "0"`},
+		{"Bad Digits", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "30;X", 0, 0, false, `otp: invalid KeePass TOTP digits "X"`},
+		{"Unsupported Digits", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "30;12", 0, 0, false,
+			"otp: unsupported number of digits 12, must be between 1 and 10"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			k, err := ParseKeePassTOTP(test.Seed, test.Settings)
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(k.Key, key) {
+				t.Errorf("Key did not match. Expected %x and got %x.\n", key, k.Key)
+			}
+			if k.Digits != test.Digits {
+				t.Errorf("Digits did not match. Expected %d and got %d.\n", test.Digits, k.Digits)
+			}
+			if k.StepSizeSeconds != test.StepSize {
+				t.Errorf("Step size did not match. Expected %d and got %d.\n", test.StepSize, k.StepSizeSeconds)
+			}
+			if k.Steam != test.Steam {
+				t.Errorf("Steam did not match. Expected %t and got %t.\n", test.Steam, k.Steam)
+			}
+		})
+	}
+}
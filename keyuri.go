@@ -0,0 +1,111 @@
+package otp
+
+import (
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// KeyURI describes a TOTP or HOTP key for enrollment in an authenticator app. String returns
+// it as an otpauth:// provisioning URI, typically presented to the user as a QR code.
+// Zero values for HashProvider, Digits and StepSizeSeconds default to DefaultHashProvider,
+// DefaultDigits and DefaultStepSizeSeconds as they do for TOTPValidator.
+type KeyURI struct {
+	Issuer          string
+	Account         string
+	Key             []byte
+	Digits          Digits
+	StepSizeSeconds int
+	HashProvider    func() hash.Hash
+	// HOTP writes an hotp URI with Counter as its counter parameter rather than a totp URI.
+	// StepSizeSeconds is ignored for HOTP.
+	HOTP    bool
+	Counter int64
+	// Steam marks a Steam Guard key, written as the non-standard encoder=steam parameter
+	// understood by KeePassXC and other apps that display Steam Guard codes.
+	Steam bool
+	// Image is the URL of an icon for the issuer, written as the image parameter. It is a
+	// non-standard extension shown by some authenticator apps and ignored by others.
+	Image string
+}
+
+// String returns the otpauth:// URI for k which can be read back with ParseKeyURI.
+// A HashProvider that isn't registered with RegisterHash is written as an UNKNOWN algorithm
+// so it is rejected by apps rather than silently treated as SHA1.
+func (k KeyURI) String() string {
+	return k.uri(secretEncoding.EncodeToString(k.Key))
+}
+
+// StringRedacted returns the otpauth:// URI for k as String does but with REDACTED in place
+// of the secret, so the URI can be shared in tickets and documentation for debugging
+// without leaking the key.
+func (k KeyURI) StringRedacted() string {
+	return k.uri("REDACTED")
+}
+
+// uri returns the otpauth:// URI for k with secret as its secret parameter.
+func (k KeyURI) uri(secret string) string {
+	label := escapeURIComponent(k.Account)
+	if k.Issuer != "" {
+		label = escapeURIComponent(k.Issuer) + ":" + label
+	}
+
+	hashProvider := k.HashProvider
+	if hashProvider == nil {
+		hashProvider = DefaultHashProvider
+	}
+	algorithm, err := HashName(hashProvider)
+	if err != nil {
+		algorithm = "UNKNOWN"
+	}
+
+	digits := k.Digits
+	if digits == 0 {
+		digits = DefaultDigits
+	}
+
+	stepSizeSeconds := k.StepSizeSeconds
+	if stepSizeSeconds == 0 {
+		stepSizeSeconds = DefaultStepSizeSeconds
+	}
+
+	var b strings.Builder
+	if k.HOTP {
+		b.WriteString("otpauth://hotp/")
+	} else {
+		b.WriteString("otpauth://totp/")
+	}
+	b.WriteString(label)
+	b.WriteString("?secret=")
+	b.WriteString(secret)
+	if k.Issuer != "" {
+		b.WriteString("&issuer=")
+		b.WriteString(escapeURIComponent(k.Issuer))
+	}
+	b.WriteString("&algorithm=")
+	b.WriteString(algorithm)
+	b.WriteString("&digits=")
+	b.WriteString(strconv.Itoa(digits.Length()))
+	if k.HOTP {
+		b.WriteString("&counter=")
+		b.WriteString(strconv.FormatInt(k.Counter, 10))
+	} else {
+		b.WriteString("&period=")
+		b.WriteString(strconv.Itoa(stepSizeSeconds))
+	}
+	if k.Steam {
+		b.WriteString("&encoder=steam")
+	}
+	if k.Image != "" {
+		b.WriteString("&image=")
+		b.WriteString(escapeURIComponent(k.Image))
+	}
+	return b.String()
+}
+
+// escapeURIComponent percent-encodes s for use in a URI label or parameter. Spaces are
+// written as %20 rather than + as some authenticator apps don't decode + in labels.
+func escapeURIComponent(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
@@ -0,0 +1,238 @@
+package otp
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"strconv"
+	"testing"
+)
+
+func TestKeyURIString(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name   string
+		KeyURI KeyURI
+		URI    string
+	}{
+		{"Defaults", KeyURI{Account: "alice@example.com", Key: key},
+			"otpauth://totp/alice%40example.com?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&algorithm=SHA1&digits=6&period=30"},
+		{"Issuer", KeyURI{Issuer: "ACME Co", Account: "john.doe", Key: key},
+			"otpauth://totp/ACME%20Co:john.doe?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&issuer=ACME%20Co&algorithm=SHA1&digits=6&period=30"},
+		{"Reserved Characters", KeyURI{Issuer: "A:B&C", Account: "x=y?", Key: key},
+			"otpauth://totp/A%3AB%26C:x%3Dy%3F?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&issuer=A%3AB%26C&algorithm=SHA1&digits=6&period=30"},
+		{"SHA512", KeyURI{Account: "alice", Key: key, HashProvider: sha512.New, Digits: EightDigits, StepSizeSeconds: 60},
+			"otpauth://totp/alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&algorithm=SHA512&digits=8&period=60"},
+		{"Image", KeyURI{Account: "alice", Key: key, Image: "https://example.com/logo.png?size=64"},
+			"otpauth://totp/alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&algorithm=SHA1&digits=6&period=30&image=https%3A%2F%2Fexample.com%2Flogo.png%3Fsize%3D64"},
+		{"Steam", KeyURI{Issuer: "Steam", Account: "alice", Key: key, Digits: Digits(100000), Steam: true},
+			"otpauth://totp/Steam:alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&issuer=Steam&algorithm=SHA1&digits=5&period=30&encoder=steam"},
+		{"Unknown Algorithm", KeyURI{Account: "alice", Key: []byte{0xff}, HashProvider: md5.New},
+			"otpauth://totp/alice?secret=74&algorithm=UNKNOWN&digits=6&period=30"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			uri := test.KeyURI.String()
+			if uri != test.URI {
+				t.Errorf("URI did not match. Expected %s and got %s.\n", test.URI, uri)
+			}
+		})
+	}
+}
+
+func TestKeyURIRoundTrip(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Issuer       string
+		Account      string
+		HashProvider func() hash.Hash
+		Digits       Digits
+		Algorithm    string
+	}{
+		{"SHA1", "Example Inc", "alice smith@example.com", sha1.New, SixDigits, "SHA1"},
+		{"SHA256", "Example Inc", "alice smith@example.com", sha256.New, SevenDigits, "SHA256"},
+		{"SHA512", "Example Inc", "alice smith@example.com", sha512.New, TenDigits, "SHA512"},
+		{"Colon In Issuer", "Acme:Inc", "alice", sha1.New, SixDigits, "SHA1"},
+		{"Colon In Account", "Acme", "alice:work", sha1.New, SixDigits, "SHA1"},
+		{"Percent Signs", "100% Acme", "50%off", sha1.New, SixDigits, "SHA1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			k := KeyURI{
+				Issuer:          test.Issuer,
+				Account:         test.Account,
+				Key:             []byte("12345678901234567890123456789012"),
+				Digits:          test.Digits,
+				StepSizeSeconds: 45,
+				HashProvider:    test.HashProvider,
+			}
+
+			validator, issuer, account, err := ParseURI(k.String())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if issuer != k.Issuer {
+				t.Errorf("Issuer did not match. Expected %q and got %q.\n", k.Issuer, issuer)
+			}
+			if account != k.Account {
+				t.Errorf("Account did not match. Expected %q and got %q.\n", k.Account, account)
+			}
+			if !bytes.Equal(validator.Key, k.Key) {
+				t.Errorf("Key did not match. Expected %x and got %x.\n", k.Key, validator.Key)
+			}
+			if validator.Digits != k.Digits {
+				t.Errorf("Digits did not match. Expected %d and got %d.\n", k.Digits, validator.Digits)
+			}
+			if validator.StepSizeSeconds != k.StepSizeSeconds {
+				t.Errorf("Step size did not match. Expected %d and got %d.\n", k.StepSizeSeconds, validator.StepSizeSeconds)
+			}
+			if name, _ := HashName(validator.HashProvider); name != test.Algorithm {
+				t.Errorf("Algorithm did not match. Expected %s and got %s.\n", test.Algorithm, name)
+			}
+		})
+	}
+}
+
+func TestParseKeyURI(t *testing.T) {
+	tests := []struct {
+		Name  string
+		URI   string
+		Image string
+	}{
+		{"No Image", "otpauth://totp/alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", ""},
+		{"Image", "otpauth://totp/alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&image=https%3A%2F%2Fexample.com%2Flogo.png",
+			"https://example.com/logo.png"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			k, err := ParseKeyURI(test.URI)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if k.Image != test.Image {
+				t.Errorf("Image did not match. Expected %q and got %q.\n", test.Image, k.Image)
+			}
+			if k.Account != "alice" || !bytes.Equal(k.Key, []byte("12345678901234567890")) {
+				t.Errorf("KeyURI did not match. Got %+v.\n", k)
+			}
+
+			// parsing and writing again is stable
+			uri := k.String()
+			reparsed, err := ParseKeyURI(uri)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if again := reparsed.String(); again != uri {
+				t.Errorf("URI did not match. Expected %s and got %s.\n", uri, again)
+			}
+		})
+	}
+}
+
+func TestParseKeyURIHOTP(t *testing.T) {
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	tests := []struct {
+		Name    string
+		URI     string
+		Counter int64
+		Err     string
+	}{
+		{"Counter", "otpauth://hotp/alice?secret=" + secret + "&counter=42", 42, ""},
+		{"Zero Counter", "otpauth://hotp/alice?secret=" + secret + "&counter=0", 0, ""},
+		{"Missing Counter", "otpauth://hotp/alice?secret=" + secret, 0, "otp: hotp URI has no counter"},
+		{"Negative Counter", "otpauth://hotp/alice?secret=" + secret + "&counter=-1", 0, `otp: invalid counter "-1"`},
+		{"Bad Counter", "otpauth://hotp/alice?secret=" + secret + "&counter=one", 0, `otp: invalid counter "one"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			k, err := ParseKeyURI(test.URI)
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !k.HOTP {
+				t.Error("URI was not parsed as hotp")
+			}
+			if k.Counter != test.Counter {
+				t.Errorf("Counter did not match. Expected %d and got %d.\n", test.Counter, k.Counter)
+			}
+			expected := "otpauth://hotp/alice?secret=" + secret + "&algorithm=SHA1&digits=6&counter=" + strconv.FormatInt(test.Counter, 10)
+			if uri := k.String(); uri != expected {
+				t.Errorf("URI did not match. Expected %s and got %s.\n", expected, uri)
+			}
+		})
+	}
+}
+
+func TestParseKeyURIDigits(t *testing.T) {
+	const prefix = "otpauth://totp/alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&algorithm=SHA1&digits="
+
+	tests := []struct {
+		Name   string
+		Digits string
+		Err    string
+	}{
+		{"Four", "4", ""},
+		{"Nine", "9", ""},
+		{"Ten", "10", ""},
+		{"Zero", "0", "otp: unsupported number of digits 0, must be between 1 and 10"},
+		{"Eleven", "11", "otp: unsupported number of digits 11, must be between 1 and 10"},
+		{"Negative", "-6", "otp: unsupported number of digits -6, must be between 1 and 10"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			uri := prefix + test.Digits + "&period=30"
+
+			k, err := ParseKeyURI(uri)
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if length := strconv.Itoa(k.Digits.Length()); length != test.Digits {
+				t.Errorf("Digits did not match. Expected %s and got %s.\n", test.Digits, length)
+			}
+			if again := k.String(); again != uri {
+				t.Errorf("URI did not match. Expected %s and got %s.\n", uri, again)
+			}
+		})
+	}
+}
+
+func TestKeyURIStringRedacted(t *testing.T) {
+	k := KeyURI{
+		Issuer:  "Example",
+		Account: "alice",
+		Key:     []byte("12345678901234567890"),
+		Digits:  EightDigits,
+		Image:   "https://example.com/logo.png",
+	}
+
+	expected := "otpauth://totp/Example:alice?secret=REDACTED&issuer=Example&algorithm=SHA1&digits=8&period=30&image=https%3A%2F%2Fexample.com%2Flogo.png"
+	if uri := k.StringRedacted(); uri != expected {
+		t.Errorf("URI did not match. Expected %s and got %s.\n", expected, uri)
+	}
+	if uri := k.String(); uri == k.StringRedacted() {
+		t.Errorf("String was redacted: %s", uri)
+	}
+}
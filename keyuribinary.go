@@ -0,0 +1,196 @@
+package otp
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// keyURIBinaryVersion is the version of the KeyURI binary encoding written by
+// MarshalBinary.
+const keyURIBinaryVersion = 1
+
+// Flags of the KeyURI binary encoding.
+const (
+	keyURIFlagHOTP  = 1 << iota // an HOTP KeyURI
+	keyURIFlagSteam             // a Steam Guard KeyURI
+)
+
+// ErrTruncatedKeyURI is returned by KeyURI.UnmarshalBinary for data that ends early.
+var ErrTruncatedKeyURI = errors.New("otp: key URI data is truncated")
+
+var (
+	_ encoding.BinaryMarshaler   = KeyURI{}
+	_ encoding.BinaryUnmarshaler = (*KeyURI)(nil)
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding k more compactly than its
+// URI for transfer between devices without a QR code. The encoding starts with a version
+// byte followed by the type, digits, algorithm, period, counter, secret, issuer, account
+// and image. Defaults are applied as for String. An error is returned if HashProvider is
+// not registered.
+func (k KeyURI) MarshalBinary() ([]byte, error) {
+	hashProvider := k.HashProvider
+	if hashProvider == nil {
+		hashProvider = DefaultHashProvider
+	}
+	algorithm, err := HashName(hashProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	digits := k.Digits
+	if digits == 0 {
+		digits = DefaultDigits
+	}
+
+	stepSizeSeconds := k.StepSizeSeconds
+	if stepSizeSeconds == 0 {
+		stepSizeSeconds = DefaultStepSizeSeconds
+	}
+
+	var flags byte
+	if k.HOTP {
+		flags |= keyURIFlagHOTP
+	}
+	if k.Steam {
+		flags |= keyURIFlagSteam
+	}
+
+	b := []byte{keyURIBinaryVersion, flags, byte(digits.Length())}
+	b = appendUvarintBytes(b, []byte(algorithm))
+	b = appendUvarint(b, uint64(stepSizeSeconds))
+	b = appendSignedVarint(b, k.Counter)
+	b = appendUvarintBytes(b, k.Key)
+	b = appendUvarintBytes(b, []byte(k.Issuer))
+	b = appendUvarintBytes(b, []byte(k.Account))
+	b = appendUvarintBytes(b, []byte(k.Image))
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding data written by
+// MarshalBinary into k. An error is returned for an unsupported version, truncated data or
+// an unregistered algorithm.
+func (k *KeyURI) UnmarshalBinary(data []byte) error {
+	r := keyURIReader{data}
+
+	header, err := r.next(3)
+	if err != nil {
+		return err
+	}
+	if header[0] != keyURIBinaryVersion {
+		return fmt.Errorf("otp: unsupported key URI data version %d", header[0])
+	}
+
+	digits, err := NewDigits(int(header[2]))
+	if err != nil {
+		return err
+	}
+
+	algorithm, err := r.bytes()
+	if err != nil {
+		return err
+	}
+	hashProvider, err := HashProviderByName(string(algorithm))
+	if err != nil {
+		return err
+	}
+
+	period, err := r.uvarint()
+	if err != nil {
+		return err
+	}
+	if period == 0 || period > uint64(maxInt) {
+		return fmt.Errorf("otp: invalid period %d", period)
+	}
+
+	counter, err := r.varint()
+	if err != nil {
+		return err
+	}
+
+	var fields [4][]byte
+	for i := range fields {
+		if fields[i], err = r.bytes(); err != nil {
+			return err
+		}
+	}
+
+	*k = KeyURI{
+		Issuer:          string(fields[1]),
+		Account:         string(fields[2]),
+		Key:             append([]byte(nil), fields[0]...),
+		Digits:          digits,
+		StepSizeSeconds: int(period),
+		HashProvider:    hashProvider,
+		HOTP:            header[1]&keyURIFlagHOTP != 0,
+		Counter:         counter,
+		Steam:           header[1]&keyURIFlagSteam != 0,
+		Image:           string(fields[3]),
+	}
+	return nil
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendSignedVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+func appendUvarintBytes(b []byte, v []byte) []byte {
+	return append(appendUvarint(b, uint64(len(v))), v...)
+}
+
+// keyURIReader decodes the fields of the KeyURI binary encoding.
+type keyURIReader struct {
+	b []byte
+}
+
+func (r *keyURIReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.b)
+	if n == 0 {
+		return 0, ErrTruncatedKeyURI
+	}
+	if n < 0 {
+		return 0, errors.New("otp: invalid varint in key URI data")
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+func (r *keyURIReader) varint() (int64, error) {
+	v, n := binary.Varint(r.b)
+	if n == 0 {
+		return 0, ErrTruncatedKeyURI
+	}
+	if n < 0 {
+		return 0, errors.New("otp: invalid varint in key URI data")
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+func (r *keyURIReader) bytes() ([]byte, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.b)) {
+		return nil, ErrTruncatedKeyURI
+	}
+	return r.next(int(n))
+}
+
+func (r *keyURIReader) next(n int) ([]byte, error) {
+	if n > len(r.b) {
+		return nil, ErrTruncatedKeyURI
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v, nil
+}
@@ -0,0 +1,77 @@
+package otp
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"testing"
+)
+
+func TestKeyURIBinaryRoundTrip(t *testing.T) {
+	tests := []struct {
+		Name   string
+		KeyURI KeyURI
+	}{
+		{"Defaults", KeyURI{Account: "alice", Key: []byte("12345678901234567890")}},
+		{"TOTP", KeyURI{Issuer: "Acme:Inc", Account: "alice@example.com", Key: []byte("12345678901234567890"),
+			Digits: EightDigits, StepSizeSeconds: 60, HashProvider: sha512.New, Image: "https://example.com/logo.png"}},
+		{"Steam", KeyURI{Issuer: "Steam", Account: "alice", Key: []byte("12345678901234567890"), Digits: Digits(100000), Steam: true}},
+		{"HOTP", KeyURI{Issuer: "Example", Account: "bob", Key: []byte("12345678901234567890"), HOTP: true, Counter: 1 << 40}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			data, err := test.KeyURI.MarshalBinary()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if uri := test.KeyURI.String(); len(data) >= len(uri) {
+				t.Errorf("Binary encoding of %d bytes is not smaller than the %d byte URI", len(data), len(uri))
+			}
+
+			var k KeyURI
+			if err := k.UnmarshalBinary(data); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if uri, expected := k.String(), test.KeyURI.String(); uri != expected {
+				t.Errorf("URI did not match. Expected %s and got %s.\n", expected, uri)
+			}
+			if k.HOTP != test.KeyURI.HOTP || k.Counter != test.KeyURI.Counter {
+				t.Errorf("Counter did not match. Expected %t, %d and got %t, %d.\n", test.KeyURI.HOTP, test.KeyURI.Counter, k.HOTP, k.Counter)
+			}
+
+			// the decoded key doesn't share the data
+			data[len(data)-1] ^= 0xff
+			if !bytes.Equal(k.Key, test.KeyURI.Key) {
+				t.Errorf("Key did not match. Expected %x and got %x.\n", test.KeyURI.Key, k.Key)
+			}
+		})
+	}
+}
+
+func TestKeyURIUnmarshalBinaryErrors(t *testing.T) {
+	data, err := KeyURI{Account: "alice", Key: []byte("12345678901234567890")}.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		Name string
+		Data []byte
+		Err  string
+	}{
+		{"Empty", nil, ErrTruncatedKeyURI.Error()},
+		{"Truncated", data[:len(data)-1], ErrTruncatedKeyURI.Error()},
+		{"Version", append([]byte{2}, data[1:]...), "otp: unsupported key URI data version 2"},
+		{"Digits", append([]byte{1, 0, 11}, data[3:]...), "otp: unsupported number of digits 11, must be between 1 and 10"},
+		{"Algorithm", append([]byte{1, 0, 6, 3, 'M', 'D', '5'}, data[8:]...), `otp: unsupported algorithm "MD5"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var k KeyURI
+			if err := k.UnmarshalBinary(test.Data); err == nil || err.Error() != test.Err {
+				t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+			}
+		})
+	}
+}
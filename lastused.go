@@ -0,0 +1,23 @@
+package otp
+
+import (
+	"time"
+)
+
+// LastUsed returns the time of the most recent successful validation, or the zero time if
+// no code has been accepted. Validations that only read the validator, such as
+// ValidateTOTPCode, are not counted. It is safe to call concurrently with validation.
+func (tc *TOTPValidator) LastUsed() time.Time {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	return tc.LastSuccess
+}
+
+// recordSuccessLocked updates LastSuccess to now unless a later success is already
+// recorded. Callers must hold tc.mu.
+func (tc *TOTPValidator) recordSuccessLocked(now time.Time) {
+	if now.After(tc.LastSuccess) {
+		tc.LastSuccess = now
+	}
+}
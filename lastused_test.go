@@ -0,0 +1,90 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLastUsed(t *testing.T) {
+	validator := &TOTPValidator{
+		Key:             []byte("12345678901234567890"),
+		Digits:          EightDigits,
+		PastTolerance:   30 * time.Second,
+		FutureTolerance: 30 * time.Second,
+	}
+
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	if used := validator.LastUsed(); !used.IsZero() {
+		t.Errorf("Expected zero LastUsed but got %s", used)
+	}
+
+	validator.VerifyAndConsume(testTime, 7081803)
+	if used := validator.LastUsed(); !used.IsZero() {
+		t.Errorf("Failed validation updated LastUsed to %s", used)
+	}
+
+	// ValidateTOTPCode only reads the validator
+	validator.ValidateTOTPCode(testTime, 7081804)
+	if used := validator.LastUsed(); !used.IsZero() {
+		t.Errorf("ValidateTOTPCode updated LastUsed to %s", used)
+	}
+
+	validator.VerifyAndConsume(testTime, 89731029)
+	if used := validator.LastUsed(); !used.Equal(testTime) {
+		t.Errorf("LastUsed did not match. Expected %s and got %s.\n", testTime, used)
+	}
+
+	// an earlier success doesn't move LastUsed backwards
+	validator.VerifyAndConsume(testTime.Add(-time.Second), 7081804)
+	if used := validator.LastUsed(); !used.Equal(testTime) {
+		t.Errorf("LastUsed did not match. Expected %s and got %s.\n", testTime, used)
+	}
+
+	later := testTime.Add(time.Second)
+	validator.ValidateCached(later, 14050471)
+	if used := validator.LastUsed(); !used.Equal(later) {
+		t.Errorf("LastUsed did not match. Expected %s and got %s.\n", later, used)
+	}
+
+	// ValidateCached consumed T+1 so validate T+2
+	later = later.Add(time.Second)
+	validator.ValidateStringCT(later, FormatCode(TOTPCode(sha1.New, validator.Key, EightDigits, 30, later.Add(30*time.Second)), EightDigits, FormatOptions{Pad: true}))
+	if used := validator.LastUsed(); !used.Equal(later) {
+		t.Errorf("LastUsed did not match. Expected %s and got %s.\n", later, used)
+	}
+
+	// a correct code with the wrong pin isn't a success
+	validator.ValidatePinOTP(later.Add(time.Second), "1234", "123514050471")
+	if used := validator.LastUsed(); !used.Equal(later) {
+		t.Errorf("LastUsed did not match. Expected %s and got %s.\n", later, used)
+	}
+}
+
+func TestLastUsedConcurrent(t *testing.T) {
+	validator := &TOTPValidator{
+		Key:    []byte("12345678901234567890"),
+		Digits: EightDigits,
+	}
+
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// each validates a different step so several succeed, the last at testTime
+			now := testTime.Add(-time.Duration(i) * 30 * time.Second)
+			validator.ValidateCached(now, TOTPCode(sha1.New, validator.Key, EightDigits, 30, now))
+			validator.LastUsed()
+		}(i)
+	}
+	wg.Wait()
+
+	if used := validator.LastUsed(); !used.Equal(testTime) {
+		t.Errorf("LastUsed did not match. Expected %s and got %s.\n", testTime, used)
+	}
+}
@@ -0,0 +1,100 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// assertUTCSemantics checks fn gives identical results for the same instant expressed in
+// several locations. Step computation must only depend on the instant, never on wall clock
+// fields of the time's location.
+func assertUTCSemantics(t *testing.T, instant time.Time, fn func(now time.Time) interface{}) {
+	t.Helper()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		newYork = time.FixedZone("EST", -5*60*60)
+	}
+
+	expected := fn(instant.UTC())
+	for _, loc := range []*time.Location{
+		newYork,
+		time.FixedZone("UTC+13:45", (13*60+45)*60),
+		time.FixedZone("UTC-00:30", -30*60),
+		time.Local,
+	} {
+		now := instant.In(loc)
+		if !now.Equal(instant) {
+			t.Fatalf("%s does not represent the same instant as %s", now, instant)
+		}
+
+		if actual := fn(now); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Result in %s did not match UTC. Expected %v and got %v.\n", loc, expected, actual)
+		}
+	}
+}
+
+func TestUTCSemantics(t *testing.T) {
+	key := []byte("12345678901234567890")
+	instants := []time.Time{
+		time.Date(1970, 1, 1, 0, 0, 59, 0, time.UTC),
+		time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC),
+		// New York daylight saving transitions
+		time.Date(2021, 3, 14, 7, 0, 0, 0, time.UTC),
+		time.Date(2021, 11, 7, 6, 0, 0, 0, time.UTC),
+		time.Date(2033, 5, 18, 3, 33, 20, 0, time.UTC),
+	}
+
+	tests := []struct {
+		Name string
+		Fn   func(now time.Time) interface{}
+	}{
+		{"TOTPCode", func(now time.Time) interface{} {
+			return TOTPCode(sha1.New, key, EightDigits, 30, now)
+		}},
+		{"ValidateTOTPCode", func(now time.Time) interface{} {
+			validator := &TOTPValidator{Key: key, Digits: EightDigits, PastTolerance: 30 * time.Second}
+			code := TOTPCode(sha1.New, key, EightDigits, 30, now.Add(-30*time.Second).UTC())
+			ok, tMatch := validator.ValidateTOTPCode(now, code)
+			return []interface{}{ok, tMatch}
+		}},
+		{"ValidateCached", func(now time.Time) interface{} {
+			validator := &TOTPValidator{Key: key, Digits: EightDigits}
+			return validator.ValidateCached(now, TOTPCode(sha1.New, key, EightDigits, 30, now.UTC()))
+		}},
+		{"WindowBitmap", func(now time.Time) interface{} {
+			validator := &TOTPValidator{Key: key, Digits: EightDigits, PastTolerance: time.Minute, FutureTolerance: time.Minute}
+			return validator.WindowBitmap(now, TOTPCode(sha1.New, key, EightDigits, 30, now.UTC()))
+		}},
+		{"IsNearBoundary", func(now time.Time) interface{} {
+			validator := &TOTPValidator{}
+			return validator.IsNearBoundary(now, 5*time.Second)
+		}},
+		{"NextStepTime", func(now time.Time) interface{} {
+			return NextStepTime(30, now).Unix()
+		}},
+		{"TimeStep", func(now time.Time) interface{} {
+			return TimeStep(30, now)
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			for _, instant := range instants {
+				assertUTCSemantics(t, instant, test.Fn)
+			}
+		})
+	}
+}
+
+func TestNextStepTimeLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+13:45", (13*60+45)*60)
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC).In(loc)
+
+	next := NextStepTime(30, now)
+	if next.Location() != loc {
+		t.Errorf("Location did not match. Expected %s and got %s.\n", loc, next.Location())
+	}
+}
@@ -0,0 +1,218 @@
+package otp
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrTruncatedMigration is returned by ParseMigration when the payload ends part way
+// through an account.
+var ErrTruncatedMigration = errors.New("otp: migration payload is truncated")
+
+// MigrationAccount is an account exported by Google Authenticator.
+// Algorithm is SHA1, SHA256, SHA512 or MD5, or empty when unspecified. Type is totp or
+// hotp, or empty when unspecified. Digits is zero when unspecified. Enum values unknown
+// to this package are kept as their number so they can be reported, e.g. "ALGORITHM_7".
+type MigrationAccount struct {
+	Name      string
+	Issuer    string
+	Secret    []byte
+	Algorithm string
+	Digits    Digits
+	Type      string
+	Counter   int64
+}
+
+// Validator returns a TOTPValidator for the account. Unspecified fields take the
+// TOTPValidator defaults. An error is returned for hotp accounts and algorithms this
+// package doesn't support.
+func (a MigrationAccount) Validator() (*TOTPValidator, error) {
+	switch a.Type {
+	case "", "totp":
+	case "hotp":
+		return nil, errors.New("otp: hotp accounts are not supported")
+	default:
+		return nil, fmt.Errorf("otp: unsupported OTP type %q", a.Type)
+	}
+
+	validator := &TOTPValidator{
+		Key:    a.Secret,
+		Digits: a.Digits,
+	}
+
+	if a.Algorithm != "" {
+		hashProvider, err := HashProviderByName(a.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		validator.HashProvider = hashProvider
+	}
+
+	return validator, nil
+}
+
+// Enum names of the Google Authenticator migration payload.
+var (
+	migrationAlgorithms = []string{"", "SHA1", "SHA256", "SHA512", "MD5"}
+	migrationDigits     = []Digits{0, SixDigits, EightDigits}
+	migrationTypes      = []string{"", "hotp", "totp"}
+)
+
+// ParseMigration parses an otpauth-migration://offline?data=... URI, as produced by the
+// export QR codes of Google Authenticator, into its accounts. Large exports are split
+// across several QR codes, each of which is a complete payload, so a batch is imported
+// by parsing each code in turn. If the payload is truncated the accounts decoded before
+// the truncation are returned along with ErrTruncatedMigration.
+func ParseMigration(uri string) ([]MigrationAccount, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("otp: invalid URI: %w", err)
+	}
+
+	if !strings.EqualFold(u.Scheme, "otpauth-migration") {
+		return nil, fmt.Errorf("otp: unsupported URI scheme %q", u.Scheme)
+	}
+
+	data := u.Query().Get("data")
+	if data == "" {
+		return nil, errors.New("otp: migration URI has no data")
+	}
+	// an unescaped + in the data is decoded as a space
+	data = strings.Replace(data, " ", "+", -1)
+
+	payload, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "="))
+	if err != nil {
+		return nil, fmt.Errorf("otp: invalid migration data: %w", err)
+	}
+
+	var accounts []MigrationAccount
+	r := protoReader{payload}
+	for len(r.b) > 0 {
+		f, err := r.field()
+		if err != nil {
+			return accounts, err
+		}
+		// the remaining fields describe the batch and version
+		if f.num != 1 {
+			continue
+		}
+
+		account, err := parseMigrationAccount(f.bytes)
+		if err != nil {
+			return accounts, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// parseMigrationAccount parses an OtpParameters message.
+func parseMigrationAccount(b []byte) (MigrationAccount, error) {
+	var account MigrationAccount
+
+	r := protoReader{b}
+	for len(r.b) > 0 {
+		f, err := r.field()
+		if err != nil {
+			return account, err
+		}
+
+		switch f.num {
+		case 1:
+			account.Secret = f.bytes
+		case 2:
+			account.Name = string(f.bytes)
+		case 3:
+			account.Issuer = string(f.bytes)
+		case 4:
+			account.Algorithm = migrationEnum(migrationAlgorithms, "ALGORITHM", f.varint)
+		case 5:
+			if f.varint < uint64(len(migrationDigits)) {
+				account.Digits = migrationDigits[f.varint]
+			}
+		case 6:
+			account.Type = migrationEnum(migrationTypes, "type", f.varint)
+		case 7:
+			account.Counter = int64(f.varint)
+		}
+	}
+
+	return account, nil
+}
+
+func migrationEnum(names []string, prefix string, value uint64) string {
+	if value < uint64(len(names)) {
+		return names[value]
+	}
+	return prefix + "_" + strconv.FormatUint(value, 10)
+}
+
+// protoReader decodes the fields of a protocol buffer message.
+type protoReader struct {
+	b []byte
+}
+
+// protoField is a decoded field. Only the value matching its wire type is set.
+type protoField struct {
+	num    int
+	varint uint64
+	bytes  []byte
+}
+
+// field reads the next field. Fixed size fields are skipped leaving only num set.
+func (r *protoReader) field() (protoField, error) {
+	tag, err := r.varint()
+	if err != nil {
+		return protoField{}, err
+	}
+
+	f := protoField{num: int(tag >> 3)}
+	switch wireType := tag & 7; wireType {
+	case 0:
+		f.varint, err = r.varint()
+	case 1:
+		_, err = r.next(8)
+	case 2:
+		var n uint64
+		if n, err = r.varint(); err != nil {
+			break
+		}
+		if n > uint64(len(r.b)) {
+			return protoField{}, ErrTruncatedMigration
+		}
+		f.bytes, err = r.next(int(n))
+	case 5:
+		_, err = r.next(4)
+	default:
+		err = fmt.Errorf("otp: unsupported protobuf wire type %d", wireType)
+	}
+
+	return f, err
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.b)
+	if n == 0 {
+		return 0, ErrTruncatedMigration
+	}
+	if n < 0 {
+		return 0, errors.New("otp: invalid protobuf varint")
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+func (r *protoReader) next(n int) ([]byte, error) {
+	if n > len(r.b) {
+		return nil, ErrTruncatedMigration
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v, nil
+}
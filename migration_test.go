@@ -0,0 +1,151 @@
+package otp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func appendVarintField(b []byte, num int, v uint64) []byte {
+	b = appendVarint(b, uint64(num)<<3)
+	return appendVarint(b, v)
+}
+
+func appendBytesField(b []byte, num int, v []byte) []byte {
+	b = appendVarint(b, uint64(num)<<3|2)
+	b = appendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func migrationURI(payload []byte) string {
+	return "otpauth-migration://offline?data=" + url.QueryEscape(base64.StdEncoding.EncodeToString(payload))
+}
+
+func TestParseMigration(t *testing.T) {
+	secret := []byte("12345678901234567890")
+
+	var totp []byte
+	totp = appendBytesField(totp, 1, secret)
+	totp = appendBytesField(totp, 2, []byte("Example:alice@example.com"))
+	totp = appendBytesField(totp, 3, []byte("Example"))
+	totp = appendVarintField(totp, 4, 2)
+	totp = appendVarintField(totp, 5, 2)
+	totp = appendVarintField(totp, 6, 2)
+
+	var hotp []byte
+	hotp = appendBytesField(hotp, 1, []byte{0xff})
+	hotp = appendBytesField(hotp, 2, []byte("bob"))
+	hotp = appendVarintField(hotp, 4, 9)
+	hotp = appendVarintField(hotp, 6, 1)
+	hotp = appendVarintField(hotp, 7, 300)
+
+	var payload []byte
+	payload = appendBytesField(payload, 1, totp)
+	payload = appendBytesField(payload, 1, hotp)
+	payload = appendVarintField(payload, 2, 1)
+	payload = appendVarintField(payload, 3, 2)
+	payload = appendVarintField(payload, 4, 1)
+	payload = appendVarintField(payload, 5, 12345)
+
+	totpAccount := MigrationAccount{
+		Name:      "Example:alice@example.com",
+		Issuer:    "Example",
+		Secret:    secret,
+		Algorithm: "SHA256",
+		Digits:    EightDigits,
+		Type:      "totp",
+	}
+	hotpAccount := MigrationAccount{
+		Name:      "bob",
+		Secret:    []byte{0xff},
+		Algorithm: "ALGORITHM_9",
+		Type:      "hotp",
+		Counter:   300,
+	}
+
+	tests := []struct {
+		Name     string
+		URI      string
+		Accounts []MigrationAccount
+		Err      string
+	}{
+		{"Accounts", migrationURI(payload), []MigrationAccount{totpAccount, hotpAccount}, ""},
+		{"Empty Batch", migrationURI(appendVarintField(nil, 2, 1)), nil, ""},
+		{"Unescaped Data", "otpauth-migration://offline?data=" + base64.RawStdEncoding.EncodeToString(payload),
+			[]MigrationAccount{totpAccount, hotpAccount}, ""},
+		{"Truncated Account", migrationURI(payload[:10]), nil, ErrTruncatedMigration.Error()},
+		{"Truncated Batch", migrationURI(payload[:len(totp)+4]), []MigrationAccount{totpAccount}, ErrTruncatedMigration.Error()},
+		{"Wrong Scheme", "otpauth://totp/alice?secret=GEZDGNBVGY3TQOJQ", nil, `otp: unsupported URI scheme "otpauth"`},
+		{"Missing Data", "otpauth-migration://offline", nil, "otp: migration URI has no data"},
+		{"Bad Data", "otpauth-migration://offline?data=%25%25", nil, "otp: invalid migration data: illegal base64 data at input byte 0"},
+		{"Bad Wire Type", migrationURI([]byte{0x0b}), nil, "otp: unsupported protobuf wire type 3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			accounts, err := ParseMigration(test.URI)
+			if test.Err == "" && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if test.Err != "" && (err == nil || err.Error() != test.Err) {
+				t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+			}
+			if !reflect.DeepEqual(accounts, test.Accounts) {
+				t.Errorf("Accounts did not match. Expected %+v and got %+v.\n", test.Accounts, accounts)
+			}
+		})
+	}
+}
+
+func TestMigrationAccountValidator(t *testing.T) {
+	account := MigrationAccount{
+		Secret:    []byte("12345678901234567890"),
+		Algorithm: "SHA1",
+		Digits:    EightDigits,
+		Type:      "totp",
+	}
+
+	validator, err := account.Validator()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(validator.Key, account.Secret) {
+		t.Errorf("Key did not match. Expected %x and got %x.\n", account.Secret, validator.Key)
+	}
+	if ok, _ := validator.ValidateTOTPCode(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), 7081804); !ok {
+		t.Error("Code did not match")
+	}
+
+	tests := []struct {
+		Name    string
+		Account MigrationAccount
+		Err     string
+	}{
+		{"Unspecified", MigrationAccount{Secret: []byte{1}}, ""},
+		{"HOTP", MigrationAccount{Type: "hotp"}, "otp: hotp accounts are not supported"},
+		{"Unknown Type", MigrationAccount{Type: "type_3"}, `otp: unsupported OTP type "type_3"`},
+		{"MD5", MigrationAccount{Algorithm: "MD5"}, `otp: unsupported algorithm "MD5"`},
+		{"Unknown Algorithm", MigrationAccount{Algorithm: "ALGORITHM_9"}, `otp: unsupported algorithm "ALGORITHM_9"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := test.Account.Validator()
+			if test.Err == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if test.Err != "" && (err == nil || err.Error() != test.Err) {
+				t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+			}
+		})
+	}
+}
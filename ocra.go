@@ -0,0 +1,224 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ocraChallengeSize is the number of bytes the challenge is padded to in the data input.
+const ocraChallengeSize = 128
+
+// OCRAOptions holds the optional data inputs of an OCRA suite. Only the inputs the suite
+// requires are used.
+// Password is hashed with the suite's password hash function unless PasswordHash, the
+// already hashed password, is set. SessionInfo must not exceed the suite's session
+// information length and is padded with zeros. Time is required by suites with a
+// timestamp.
+type OCRAOptions struct {
+	Counter      uint64
+	Password     string
+	PasswordHash []byte
+	SessionInfo  []byte
+	Time         time.Time
+}
+
+// ocraSuite is a parsed OCRA suite string.
+type ocraSuite struct {
+	hashProvider    func() hash.Hash
+	digits          int
+	counter         bool
+	challengeFormat byte
+	passwordHash    func() hash.Hash
+	sessionLength   int
+	timeStep        time.Duration
+}
+
+// OCRA computes an OCRA challenge-response value as described in RFC 6287 for suite,
+// for example "OCRA-1:HOTP-SHA1-6:QN08" or "OCRA-1:HOTP-SHA512-8:C-QN08-PSHA1-T1M".
+// challenge is the question as text: decimal digits for QN, characters for QA and
+// hexadecimal digits for QH. The response is returned zero padded to the suite's number
+// of digits. An error is returned for a malformed or unsupported suite, a challenge that
+// doesn't match its format or a missing data input.
+func OCRA(suite string, key []byte, challenge []byte, opts OCRAOptions) (string, error) {
+	s, err := parseOCRASuite(suite)
+	if err != nil {
+		return "", err
+	}
+	if len(key) == 0 {
+		return "", ErrEmptyKey
+	}
+
+	msg := append([]byte(suite), 0)
+
+	if s.counter {
+		msg = appendUint64(msg, opts.Counter)
+	}
+
+	question, err := ocraChallenge(s.challengeFormat, challenge)
+	if err != nil {
+		return "", err
+	}
+	msg = append(msg, question...)
+
+	if s.passwordHash != nil {
+		password := opts.PasswordHash
+		if password == nil {
+			if opts.Password == "" {
+				return "", errors.New("otp: OCRA suite requires a password")
+			}
+			h := s.passwordHash()
+			h.Write([]byte(opts.Password))
+			password = h.Sum(nil)
+		}
+		if len(password) != s.passwordHash().Size() {
+			return "", fmt.Errorf("otp: OCRA password hash must be %d bytes", s.passwordHash().Size())
+		}
+		msg = append(msg, password...)
+	}
+
+	if s.sessionLength > 0 {
+		if len(opts.SessionInfo) > s.sessionLength {
+			return "", fmt.Errorf("otp: OCRA session information exceeds %d bytes", s.sessionLength)
+		}
+		session := make([]byte, s.sessionLength)
+		copy(session, opts.SessionInfo)
+		msg = append(msg, session...)
+	}
+
+	if s.timeStep > 0 {
+		if opts.Time.IsZero() {
+			return "", errors.New("otp: OCRA suite requires a time")
+		}
+		msg = appendUint64(msg, uint64(opts.Time.Unix()/int64(s.timeStep/time.Second)))
+	}
+
+	h := hmac.New(s.hashProvider, key)
+	h.Write(msg)
+
+	digits, err := NewDigits(s.digits)
+	if err != nil {
+		return "", err
+	}
+	code := int(uint64(dynamicTruncate(h.Sum(nil))) % uint64(digits))
+	return FormatCode(code, digits, FormatOptions{Pad: true}), nil
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// ocraChallenge returns the challenge in its data input form, as bytes padded with zeros
+// to ocraChallengeSize.
+func ocraChallenge(format byte, challenge []byte) ([]byte, error) {
+	var hexChallenge string
+	switch format {
+	case 'N':
+		n, ok := new(big.Int).SetString(string(challenge), 10)
+		if !ok || n.Sign() < 0 {
+			return nil, fmt.Errorf("otp: OCRA challenge %q is not decimal", challenge)
+		}
+		hexChallenge = n.Text(16)
+	case 'A':
+		hexChallenge = hex.EncodeToString(challenge)
+	case 'H':
+		hexChallenge = string(challenge)
+	}
+
+	if len(hexChallenge) > ocraChallengeSize*2 {
+		return nil, fmt.Errorf("otp: OCRA challenge exceeds %d bytes", ocraChallengeSize)
+	}
+	hexChallenge += strings.Repeat("0", ocraChallengeSize*2-len(hexChallenge))
+
+	question, err := hex.DecodeString(hexChallenge)
+	if err != nil {
+		return nil, fmt.Errorf("otp: OCRA challenge %q is not hexadecimal", challenge)
+	}
+	return question, nil
+}
+
+// parseOCRASuite parses an OCRA suite of the form
+// "OCRA-1:HOTP-<hash>-<digits>:[C-]Q<format><length>[-P<hash>|-S<length>][-T<step>]".
+func parseOCRASuite(suite string) (ocraSuite, error) {
+	var s ocraSuite
+
+	invalid := func(reason string) (ocraSuite, error) {
+		return ocraSuite{}, fmt.Errorf("otp: invalid OCRA suite %q: %s", suite, reason)
+	}
+
+	parts := strings.Split(suite, ":")
+	if len(parts) != 3 {
+		return invalid("expected three parts")
+	}
+	if parts[0] != "OCRA-1" {
+		return invalid("unsupported version")
+	}
+
+	function := strings.Split(parts[1], "-")
+	if len(function) != 3 || function[0] != "HOTP" {
+		return invalid("expected a HOTP crypto function")
+	}
+	hashProvider, err := HashProviderByName(function[1])
+	if err != nil {
+		return invalid(err.Error())
+	}
+	s.hashProvider = hashProvider
+	if s.digits, err = strconv.Atoi(function[2]); err != nil || s.digits < 4 || s.digits > MaxDigits {
+		return invalid("digits must be between 4 and 10")
+	}
+
+	inputs := strings.Split(parts[2], "-")
+	if len(inputs) > 0 && inputs[0] == "C" {
+		s.counter = true
+		inputs = inputs[1:]
+	}
+
+	if len(inputs) == 0 || len(inputs[0]) != 4 || inputs[0][0] != 'Q' {
+		return invalid("expected a challenge")
+	}
+	s.challengeFormat = inputs[0][1]
+	if !strings.ContainsRune("ANH", rune(s.challengeFormat)) {
+		return invalid("unsupported challenge format")
+	}
+	if n, err := strconv.Atoi(inputs[0][2:]); err != nil || n < 4 || n > 64 {
+		return invalid("challenge length must be between 04 and 64")
+	}
+	inputs = inputs[1:]
+
+	for _, input := range inputs {
+		switch {
+		case strings.HasPrefix(input, "P") && s.passwordHash == nil:
+			passwordHash, err := HashProviderByName(input[1:])
+			if err != nil {
+				return invalid(err.Error())
+			}
+			s.passwordHash = passwordHash
+		case strings.HasPrefix(input, "S") && s.sessionLength == 0:
+			n, err := strconv.Atoi(input[1:])
+			if err != nil || len(input) != 4 || n <= 0 {
+				return invalid("invalid session information length")
+			}
+			s.sessionLength = n
+		case strings.HasPrefix(input, "T") && s.timeStep == 0 && len(input) > 2:
+			n, err := strconv.Atoi(input[1 : len(input)-1])
+			unit := map[byte]time.Duration{'S': time.Second, 'M': time.Minute, 'H': time.Hour}[input[len(input)-1]]
+			if err != nil || n <= 0 || unit == 0 {
+				return invalid("invalid timestamp step")
+			}
+			s.timeStep = time.Duration(n) * unit
+		default:
+			return invalid(fmt.Sprintf("unexpected data input %q", input))
+		}
+	}
+
+	return s, nil
+}
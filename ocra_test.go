@@ -0,0 +1,136 @@
+package otp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6287 Appendix C keys.
+var (
+	ocraSeed   = []byte("12345678901234567890")
+	ocraSeed32 = []byte("12345678901234567890123456789012")
+	ocraSeed64 = []byte("1234567890123456789012345678901234567890123456789012345678901234")
+)
+
+func TestOCRA(t *testing.T) {
+	timestamp := time.Unix(0x132d0b6*60, 0)
+
+	tests := []struct {
+		Suite     string
+		Key       []byte
+		Challenge string
+		Opts      OCRAOptions
+		Response  string
+	}{
+		{"OCRA-1:HOTP-SHA1-6:QN08", ocraSeed, "00000000", OCRAOptions{}, "237653"},
+		{"OCRA-1:HOTP-SHA1-6:QN08", ocraSeed, "11111111", OCRAOptions{}, "243178"},
+		{"OCRA-1:HOTP-SHA1-6:QN08", ocraSeed, "55555555", OCRAOptions{}, "388898"},
+		{"OCRA-1:HOTP-SHA1-6:QN08", ocraSeed, "99999999", OCRAOptions{}, "294470"},
+		{"OCRA-1:HOTP-SHA256-8:C-QN08-PSHA1", ocraSeed32, "12345678", OCRAOptions{Counter: 0, Password: "1234"}, "65347737"},
+		{"OCRA-1:HOTP-SHA256-8:C-QN08-PSHA1", ocraSeed32, "12345678", OCRAOptions{Counter: 1, Password: "1234"}, "86775851"},
+		{"OCRA-1:HOTP-SHA256-8:C-QN08-PSHA1", ocraSeed32, "12345678", OCRAOptions{Counter: 9, Password: "1234"}, "08522129"},
+		{"OCRA-1:HOTP-SHA256-8:QN08-PSHA1", ocraSeed32, "00000000", OCRAOptions{Password: "1234"}, "83238735"},
+		{"OCRA-1:HOTP-SHA256-8:QN08-PSHA1", ocraSeed32, "44444444", OCRAOptions{Password: "1234"}, "86807031"},
+		{"OCRA-1:HOTP-SHA512-8:C-QN08", ocraSeed64, "00000000", OCRAOptions{Counter: 0}, "07016083"},
+		{"OCRA-1:HOTP-SHA512-8:C-QN08", ocraSeed64, "55555555", OCRAOptions{Counter: 5}, "34205738"},
+		{"OCRA-1:HOTP-SHA512-8:C-QN08", ocraSeed64, "99999999", OCRAOptions{Counter: 9}, "31409299"},
+		{"OCRA-1:HOTP-SHA512-8:QN08-T1M", ocraSeed64, "00000000", OCRAOptions{Time: timestamp}, "95209754"},
+		{"OCRA-1:HOTP-SHA512-8:QN08-T1M", ocraSeed64, "44444444", OCRAOptions{Time: timestamp.Add(59 * time.Second)}, "36209546"},
+		// mutual challenge-response
+		{"OCRA-1:HOTP-SHA256-8:QA08", ocraSeed32, "CLI22220SRV11110", OCRAOptions{}, "28247970"},
+		{"OCRA-1:HOTP-SHA256-8:QA08", ocraSeed32, "CLI22224SRV11114", OCRAOptions{}, "83412541"},
+		{"OCRA-1:HOTP-SHA256-8:QA08", ocraSeed32, "SRV11110CLI22220", OCRAOptions{}, "15510767"},
+		{"OCRA-1:HOTP-SHA512-8:QA08", ocraSeed64, "CLI22220SRV11110", OCRAOptions{}, "79496648"},
+		{"OCRA-1:HOTP-SHA512-8:QA08-PSHA1", ocraSeed64, "SRV11110CLI22220", OCRAOptions{Password: "1234"}, "18806276"},
+		// plain signature
+		{"OCRA-1:HOTP-SHA256-8:QA08", ocraSeed32, "SIG10000", OCRAOptions{}, "53095496"},
+		{"OCRA-1:HOTP-SHA512-8:QA10-T1M", ocraSeed64, "SIG1000000", OCRAOptions{Time: timestamp}, "77537423"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s %s %d", test.Suite, test.Challenge, test.Opts.Counter), func(t *testing.T) {
+			response, err := OCRA(test.Suite, test.Key, []byte(test.Challenge), test.Opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if response != test.Response {
+				t.Errorf("Response did not match. Expected %s and got %s.\n", test.Response, response)
+			}
+		})
+	}
+}
+
+func TestOCRAInputs(t *testing.T) {
+	pinHash, _ := hex.DecodeString("7110eda4d09e062aa5e4a390b0a572ac0d2c0220")
+
+	tests := []struct {
+		Name      string
+		Suite     string
+		Challenge string
+		Opts      OCRAOptions
+		Response  string
+	}{
+		{"Password Hash", "OCRA-1:HOTP-SHA256-8:QN08-PSHA1", "00000000", OCRAOptions{PasswordHash: pinHash}, "83238735"},
+		{"Password Hash Precedence", "OCRA-1:HOTP-SHA256-8:QN08-PSHA1", "00000000", OCRAOptions{Password: "4321", PasswordHash: pinHash}, "83238735"},
+		{"Hex Challenge", "OCRA-1:HOTP-SHA256-8:QH08", "434c49", OCRAOptions{}, ""},
+		{"Session Info", "OCRA-1:HOTP-SHA256-8:QN08-S064", "00000000", OCRAOptions{SessionInfo: []byte{1}}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			response, err := OCRA(test.Suite, ocraSeed32, []byte(test.Challenge), test.Opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if test.Response != "" && response != test.Response {
+				t.Errorf("Response did not match. Expected %s and got %s.\n", test.Response, response)
+			}
+			if len(response) != 8 {
+				t.Errorf("Response length did not match. Expected 8 and got %d.\n", len(response))
+			}
+		})
+	}
+}
+
+func TestOCRAErrors(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Suite     string
+		Key       []byte
+		Challenge string
+		Opts      OCRAOptions
+		Err       string
+	}{
+		{"Missing Parts", "OCRA-1:HOTP-SHA1-6", ocraSeed, "0000", OCRAOptions{}, "expected three parts"},
+		{"Version", "OCRA-2:HOTP-SHA1-6:QN08", ocraSeed, "0000", OCRAOptions{}, "unsupported version"},
+		{"Crypto Function", "OCRA-1:TOTP-SHA1-6:QN08", ocraSeed, "0000", OCRAOptions{}, "expected a HOTP crypto function"},
+		{"Hash", "OCRA-1:HOTP-MD5-6:QN08", ocraSeed, "0000", OCRAOptions{}, `unsupported algorithm "MD5"`},
+		{"No Truncation", "OCRA-1:HOTP-SHA1-0:QN08", ocraSeed, "0000", OCRAOptions{}, "digits must be between 4 and 10"},
+		{"Missing Challenge", "OCRA-1:HOTP-SHA1-6:C", ocraSeed, "0000", OCRAOptions{}, "expected a challenge"},
+		{"Challenge Format", "OCRA-1:HOTP-SHA1-6:QX08", ocraSeed, "0000", OCRAOptions{}, "unsupported challenge format"},
+		{"Challenge Length", "OCRA-1:HOTP-SHA1-6:QN99", ocraSeed, "0000", OCRAOptions{}, "challenge length must be between 04 and 64"},
+		{"Timestamp Step", "OCRA-1:HOTP-SHA1-6:QN08-T1X", ocraSeed, "0000", OCRAOptions{}, "invalid timestamp step"},
+		{"Session Length", "OCRA-1:HOTP-SHA1-6:QN08-S64", ocraSeed, "0000", OCRAOptions{}, "invalid session information length"},
+		{"Unexpected Input", "OCRA-1:HOTP-SHA1-6:QN08-X", ocraSeed, "0000", OCRAOptions{}, `unexpected data input "X"`},
+		{"Empty Key", "OCRA-1:HOTP-SHA1-6:QN08", nil, "0000", OCRAOptions{}, "otp: key is empty"},
+		{"Decimal Challenge", "OCRA-1:HOTP-SHA1-6:QN08", ocraSeed, "12ab", OCRAOptions{}, `otp: OCRA challenge "12ab" is not decimal`},
+		{"Hex Challenge", "OCRA-1:HOTP-SHA1-6:QH08", ocraSeed, "12xy", OCRAOptions{}, `otp: OCRA challenge "12xy" is not hexadecimal`},
+		{"Long Challenge", "OCRA-1:HOTP-SHA1-6:QA08", ocraSeed, strings.Repeat("a", 129), OCRAOptions{}, "otp: OCRA challenge exceeds 128 bytes"},
+		{"Missing Password", "OCRA-1:HOTP-SHA1-6:QN08-PSHA1", ocraSeed, "0000", OCRAOptions{}, "otp: OCRA suite requires a password"},
+		{"Password Hash Size", "OCRA-1:HOTP-SHA1-6:QN08-PSHA256", ocraSeed, "0000", OCRAOptions{PasswordHash: []byte{1}}, "otp: OCRA password hash must be 32 bytes"},
+		{"Long Session", "OCRA-1:HOTP-SHA1-6:QN08-S001", ocraSeed, "0000", OCRAOptions{SessionInfo: []byte{1, 2}}, "otp: OCRA session information exceeds 1 bytes"},
+		{"Missing Time", "OCRA-1:HOTP-SHA1-6:QN08-T30S", ocraSeed, "0000", OCRAOptions{}, "otp: OCRA suite requires a time"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := OCRA(test.Suite, test.Key, []byte(test.Challenge), test.Opts)
+			if err == nil || !strings.HasSuffix(err.Error(), test.Err) {
+				t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package otp
+
+import (
+	"fmt"
+	"hash"
+	"time"
+)
+
+// Option configures a TOTPValidator created by NewTOTPValidator.
+type Option func(*TOTPValidator)
+
+// WithDigits sets the number of digits in a code.
+func WithDigits(digits Digits) Option {
+	return func(tc *TOTPValidator) {
+		tc.Digits = digits
+	}
+}
+
+// WithStepSize sets the step size.
+func WithStepSize(stepSize time.Duration) Option {
+	return func(tc *TOTPValidator) {
+		tc.StepSize = stepSize
+	}
+}
+
+// WithHashProvider sets the hash used for the HMAC.
+func WithHashProvider(hashProvider func() hash.Hash) Option {
+	return func(tc *TOTPValidator) {
+		tc.HashProvider = hashProvider
+	}
+}
+
+// WithPastTolerance sets how far before now codes are accepted for.
+func WithPastTolerance(tolerance time.Duration) Option {
+	return func(tc *TOTPValidator) {
+		tc.PastTolerance = tolerance
+	}
+}
+
+// WithFutureTolerance sets how far after now codes are accepted for.
+func WithFutureTolerance(tolerance time.Duration) Option {
+	return func(tc *TOTPValidator) {
+		tc.FutureTolerance = tolerance
+	}
+}
+
+// WithPastSteps sets how many steps before now codes are accepted for. It takes
+// precedence over WithPastTolerance.
+func WithPastSteps(steps int) Option {
+	return func(tc *TOTPValidator) {
+		tc.PastSteps = steps
+	}
+}
+
+// WithFutureSteps sets how many steps after now codes are accepted for. It takes
+// precedence over WithFutureTolerance.
+func WithFutureSteps(steps int) Option {
+	return func(tc *TOTPValidator) {
+		tc.FutureSteps = steps
+	}
+}
+
+// WithMaxWindowSteps caps the number of steps in the tolerance window.
+func WithMaxWindowSteps(steps int) Option {
+	return func(tc *TOTPValidator) {
+		tc.MaxWindowSteps = steps
+	}
+}
+
+// WithLastT sets the last time step a code was accepted for.
+func WithLastT(lastT int) Option {
+	return func(tc *TOTPValidator) {
+		tc.LastT = lastT
+	}
+}
+
+// NewTOTPValidator returns a validator for key configured by opts. Unless overridden it
+// uses DefaultHashProvider, DefaultDigits, a DefaultStepSizeSeconds step size and no
+// tolerance. Unlike filling in a TOTPValidator directly every default is set explicitly
+// on the result.
+// An error is returned for an empty key, a nil hash provider, digits that aren't a
+// power of ten between MinDigits and MaxDigits, a step size that isn't positive or a
+// negative tolerance or step count.
+func NewTOTPValidator(key []byte, opts ...Option) (*TOTPValidator, error) {
+	tc := &TOTPValidator{
+		Key:          key,
+		HashProvider: DefaultHashProvider,
+		Digits:       DefaultDigits,
+		StepSize:     DefaultStepSizeSeconds * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(tc)
+	}
+
+	switch {
+	case len(tc.Key) == 0:
+		return nil, ErrEmptyKey
+	case tc.HashProvider == nil:
+		return nil, ErrNilHashProvider
+	case tc.StepSize <= 0:
+		return nil, fmt.Errorf("otp: step size %s must be positive", tc.StepSize)
+	case tc.PastTolerance < 0:
+		return nil, fmt.Errorf("otp: past tolerance %s must not be negative", tc.PastTolerance)
+	case tc.FutureTolerance < 0:
+		return nil, fmt.Errorf("otp: future tolerance %s must not be negative", tc.FutureTolerance)
+	case tc.PastSteps < 0:
+		return nil, fmt.Errorf("otp: past steps %d must not be negative", tc.PastSteps)
+	case tc.FutureSteps < 0:
+		return nil, fmt.Errorf("otp: future steps %d must not be negative", tc.FutureSteps)
+	}
+
+	if d, err := NewDigits(tc.Digits.Length()); err != nil || d != tc.Digits {
+		return nil, fmt.Errorf("otp: unsupported digits %d, must be a power of ten with %d to %d digits", tc.Digits, MinDigits, MaxDigits)
+	}
+
+	return tc, nil
+}
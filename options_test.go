@@ -0,0 +1,98 @@
+package otp
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+)
+
+func TestNewTOTPValidator(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name string
+		Key  []byte
+		Opts []Option
+		Err  string
+	}{
+		{"Defaults", key, nil, ""},
+		{"All Options", key, []Option{
+			WithDigits(EightDigits),
+			WithStepSize(time.Minute),
+			WithHashProvider(sha256.New),
+			WithPastTolerance(time.Minute),
+			WithFutureTolerance(30 * time.Second),
+			WithLastT(5),
+		}, ""},
+		{"Empty Key", nil, nil, "otp: key is empty"},
+		{"Nil Hash Provider", key, []Option{WithHashProvider(nil)}, "otp: hash provider is nil"},
+		{"Zero Step Size", key, []Option{WithStepSize(0)}, "otp: step size 0s must be positive"},
+		{"Negative Step Size", key, []Option{WithStepSize(-time.Second)}, "otp: step size -1s must be positive"},
+		{"Negative Past Tolerance", key, []Option{WithPastTolerance(-time.Second)}, "otp: past tolerance -1s must not be negative"},
+		{"Negative Future Tolerance", key, []Option{WithFutureTolerance(-time.Second)}, "otp: future tolerance -1s must not be negative"},
+		{"Negative Past Steps", key, []Option{WithPastSteps(-1)}, "otp: past steps -1 must not be negative"},
+		{"Negative Future Steps", key, []Option{WithFutureSteps(-1)}, "otp: future steps -1 must not be negative"},
+		{"Zero Digits", key, []Option{WithDigits(0)}, "otp: unsupported digits 0, must be a power of ten with 1 to 10 digits"},
+		{"Uneven Digits", key, []Option{WithDigits(123456)}, "otp: unsupported digits 123456, must be a power of ten with 1 to 10 digits"},
+		{"Too Many Digits", key, []Option{WithDigits(TenDigits * 10)}, "otp: unsupported digits 100000000000, must be a power of ten with 1 to 10 digits"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator, err := NewTOTPValidator(test.Key, test.Opts...)
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+				}
+				if validator != nil {
+					t.Error("Expected no validator")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if validator.HashProvider == nil || validator.Digits == 0 || validator.StepSize == 0 {
+				t.Errorf("Defaults were not set: %+v", validator)
+			}
+		})
+	}
+}
+
+func TestNewTOTPValidatorOptions(t *testing.T) {
+	key := []byte("12345678901234567890123456789012")
+
+	validator, err := NewTOTPValidator(key,
+		WithDigits(EightDigits),
+		WithStepSize(30*time.Second),
+		WithHashProvider(sha256.New),
+		WithPastTolerance(30*time.Second),
+		WithFutureTolerance(time.Minute),
+		WithLastT(0x23523EB),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if validator.Digits != EightDigits {
+		t.Errorf("Digits did not match. Expected %d and got %d.\n", EightDigits, validator.Digits)
+	}
+	if validator.PastTolerance != 30*time.Second || validator.FutureTolerance != time.Minute {
+		t.Errorf("Tolerance did not match. Got %s and %s.\n", validator.PastTolerance, validator.FutureTolerance)
+	}
+	if validator.LastT != 0x23523EB {
+		t.Errorf("LastT did not match. Expected %d and got %d.\n", 0x23523EB, validator.LastT)
+	}
+	if name, _ := HashName(validator.HashProvider); name != "SHA256" {
+		t.Errorf("Hash provider did not match. Expected SHA256 and got %s.\n", name)
+	}
+
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	// the SHA256 code for T-1 is rejected by LastT
+	if ok, _ := validator.ValidateTOTPCode(now, HOTPCode(sha256.New, key, EightDigits, 0x23523EB)); ok {
+		t.Error("Code at LastT matched")
+	}
+	if ok, tMatch := validator.ValidateTOTPCode(now, 68084774); !ok || tMatch != 0x23523EC {
+		t.Errorf("Code did not match. Got %t and %d.\n", ok, tMatch)
+	}
+}
@@ -3,40 +3,93 @@ package otp
 import (
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/subtle"
 	"encoding/binary"
+	"errors"
 	"hash"
+	"sort"
+	"sync"
 	"time"
 )
 
-// Digits configures the number of digits returned in a HOTP code
-type Digits uint32
+// Digits configures the number of digits returned in a HOTP code.
+// Codes are derived from a 31 bit value so a code has at most 2^31 possible values
+// regardless of digits. With TenDigits the code is that value unreduced.
+type Digits uint64
 
 // Common Digits configurations
 const (
 	SixDigits   Digits = 1000000
-	SevenDigits        = SixDigits * 10
-	EightDigits        = SevenDigits * 10
+	SevenDigits Digits = SixDigits * 10
+	EightDigits Digits = SevenDigits * 10
+	NineDigits  Digits = EightDigits * 10
+	TenDigits   Digits = NineDigits * 10
 )
 
 // Defaults
 const (
 	DefaultStepSizeSeconds = 30
+	DefaultMaxWindowSteps  = 10
+	DefaultDigits          = SixDigits
+)
+
+// DefaultHashProvider is the hash used when a validator, Config or KeyURI has no
+// HashProvider. It is SHA1 as for most authenticator apps. An application whose tokens
+// all use another algorithm may change it, before any codes are generated or validated
+// as it is not safe to change concurrently.
+var DefaultHashProvider = sha1.New
+
+// Errors returned by HOTPCodeErr.
+var (
+	ErrNilHashProvider = errors.New("otp: hash provider is nil")
+	ErrEmptyKey        = errors.New("otp: key is empty")
+	ErrZeroDigits      = errors.New("otp: digits is zero")
 )
 
 // HOTPCode generates a HMAC-Based One-Time Password from value as described in RFC 4226.
 // Common parameters are sha1 hash, 20 byte shared key and SixDigits output.
+// Codes are reduced from a 31 bit value so they always lie in [0, 2^31), which fits an int
+// on 32 bit platforms even for NineDigits and TenDigits.
+// HOTPCode panics if HOTPCodeErr would return an error.
 func HOTPCode(hashProvider func() hash.Hash, key []byte, digits Digits, value int64) int {
-	h := hmac.New(hashProvider, key)
-	if err := binary.Write(h, binary.BigEndian, value); err != nil {
-		// this should not ever happen
+	code, err := HOTPCodeErr(hashProvider, key, digits, value)
+	if err != nil {
 		panic(err)
 	}
+	return code
+}
+
+// HOTPCodeErr is HOTPCode returning an error rather than panicking. An error is returned
+// for a nil hashProvider, an empty key or zero digits.
+func HOTPCodeErr(hashProvider func() hash.Hash, key []byte, digits Digits, value int64) (int, error) {
+	switch {
+	case hashProvider == nil:
+		return 0, ErrNilHashProvider
+	case len(key) == 0:
+		return 0, ErrEmptyKey
+	case digits == 0:
+		return 0, ErrZeroDigits
+	}
 
-	sum := h.Sum(nil)
-	offset := sum[len(sum)-1] & 0x0f
-	snip := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return int(uint64(truncate(hashProvider, key, value)) % uint64(digits)), nil
+}
+
+// truncate computes the HMAC of value and applies the dynamic truncation of RFC 4226
+// returning a 31 bit value.
+func truncate(hashProvider func() hash.Hash, key []byte, value int64) uint32 {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(value))
 
-	return int(snip % uint32(digits))
+	h := hmac.New(hashProvider, key)
+	h.Write(counter[:])
+
+	return dynamicTruncate(h.Sum(nil))
+}
+
+// dynamicTruncate extracts the 31 bit value from an HMAC as described in RFC 4226.
+func dynamicTruncate(sum []byte) uint32 {
+	offset := sum[len(sum)-1] & 0x0f
+	return binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
 }
 
 // TOTPCode generates a Time-Based One-Time Password from a time as described in RFC 6238.
@@ -45,53 +98,273 @@ func TOTPCode(hashProvider func() hash.Hash, key []byte, digits Digits, stepSize
 	return HOTPCode(hashProvider, key, digits, int64(timeSteps(stepSizeSeconds, t)))
 }
 
+// TOTPCodeDuration generates a Time-Based One-Time Password like TOTPCode but with the step
+// size expressed as a duration. Sub-second step sizes are supported which is useful for
+// exercising code rotation in tests.
+func TOTPCodeDuration(hashProvider func() hash.Hash, key []byte, digits Digits, stepSize time.Duration, t time.Time) int {
+	return HOTPCode(hashProvider, key, digits, durationCounter(stepSize, t))
+}
+
 // TOTPValidator assists in validating a provided TOTP code.
 // Past and Future tolerance establish a range of time that codes will be accepted for.
+// MaxWindowSteps caps the number of steps in the tolerance window, which bounds the work a
+// single validation performs. Zero uses DefaultMaxWindowSteps and a negative value removes
+// the cap.
+// PastSteps and FutureSteps express the tolerances as a number of steps and take
+// precedence over PastTolerance and FutureTolerance when set.
 // LastT will restrict code acceptance to time steps after LastT.
+// ReplayStore, when set, additionally rejects any step it has seen and is marked with
+// each step accepted by a validation that records use, such as VerifyAndConsume.
+// StepSize takes precedence over StepSizeSeconds when set. Time steps are ints so on 32 bit
+// platforms step sizes below about a second overflow at current dates and aren't supported.
+// Epoch is the T0 time steps are counted from. The zero value is the Unix epoch.
+// AcceptOffsets, when set, replaces the tolerance window with the exact step offsets
+// relative to the current time step that codes will be accepted for.
+// CounterKey is the MAC key used by ValidateSignedCounter.
+// EnrolledAt, EnrollmentAge and EnrollmentTolerance widen both tolerances for newly
+// enrolled tokens, ramping down from EnrollmentTolerance to none over EnrollmentAge.
+// FixedScanSteps, when set, makes ValidateTOTPCode compute at least that many codes on
+// every call so its timing doesn't depend on the match position or LastT.
+// After rotating Key, codes for PreviousKey are also accepted until RotationGrace has
+// passed since RotatedAt.
+// LastSuccess records the time of the most recent successful validation that records
+// use.
+// Clock provides the time for Validate and defaults to SystemClock.
+// The validation methods may be called concurrently: they read LastT, LastSuccess and
+// the ReplayStore and record use under a lock, with VerifyAndConsume being the safe way
+// to advance LastT. Other fields must not be modified concurrently with validation.
 type TOTPValidator struct {
 	Key             []byte
 	StepSizeSeconds int
+	StepSize        time.Duration
+	Epoch           time.Time
 	PastTolerance   time.Duration // expected to be positive
 	FutureTolerance time.Duration
+	PastSteps       int
+	FutureSteps     int
+	MaxWindowSteps  int
 	LastT           int
+	ReplayStore     ReplayStore
+	AcceptOffsets   []int
 	HashProvider    func() hash.Hash
 	Digits          Digits
+	CounterKey      []byte
+	FixedScanSteps  int
+
+	EnrolledAt          time.Time
+	EnrollmentAge       time.Duration
+	EnrollmentTolerance time.Duration
+
+	PreviousKey   []byte
+	RotatedAt     time.Time
+	RotationGrace time.Duration
+
+	LastSuccess time.Time
+
+	Clock Clock
+
+	mu    sync.Mutex
+	index *CodeIndex
 }
 
 // ValidateTOTPCode returns a bool indicating if code is valid for the provided time.
 // It also returns a value T which can be set to TOTPValidator.LastT to prevent a valid
 // code from being reused.
+// Each step in the window is compared in constant time and the whole window is always
+// scanned, so timing does not reveal how close a guess was or where it matched.
+// ValidateTOTPCode only reads the validator: LastSuccess and the ReplayStore are not
+// updated. VerifyAndConsume validates a code and records its use.
 func (tc *TOTPValidator) ValidateTOTPCode(now time.Time, code int) (bool, int) {
-	hashProvider := tc.HashProvider
-	if hashProvider == nil {
-		hashProvider = sha1.New
-	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	return tc.validate(now, code)
+}
 
-	digits := tc.Digits
-	if digits == 0 {
-		digits = SixDigits
+// validate is ValidateTOTPCode for callers holding tc.mu.
+func (tc *TOTPValidator) validate(now time.Time, code int) (bool, int) {
+	if tc.FixedScanSteps > 0 {
+		return tc.validateFixedScan(now, code)
 	}
 
-	stepSizeSeconds := tc.StepSizeSeconds
-	if stepSizeSeconds == 0 {
-		stepSizeSeconds = DefaultStepSizeSeconds
+	return tc.match(tc.generators(now), tc.steps(now), code, tc.stepAt(tc.stepSize(), now))
+}
+
+// match compares code with the code for each of steps under each generator in constant
+// time, skipping used steps. Every step is compared whether or not an earlier one
+// matched. It returns the first step that matched, or tMatch if none did.
+func (tc *TOTPValidator) match(generators []*hotpGenerator, steps []int, code int, tMatch int) (bool, int) {
+	matched := 0
+	for _, g := range generators {
+		for _, t := range steps {
+			if tc.used(t) {
+				continue
+			}
+
+			isMatch := codesEqual(g.code(int64(t)), code)
+			tMatch = subtle.ConstantTimeSelect(isMatch&^matched, t, tMatch)
+			matched |= isMatch
+		}
 	}
 
-	tMin := timeSteps(stepSizeSeconds, now.Add(-tc.PastTolerance))
-	tMax := timeSteps(stepSizeSeconds, now.Add(tc.FutureTolerance))
-	for t := tMin; t <= tMax; t++ {
-		if t <= tc.LastT {
+	return matched == 1, tMatch
+}
+
+// generators returns a hotpGenerator for each key codes are accepted for at now, keying
+// the HMAC once for all steps. A key that can't generate codes, such as an empty Key, is
+// left out so every code is rejected rather than validation panicking.
+func (tc *TOTPValidator) generators(now time.Time) []*hotpGenerator {
+	hashProvider := tc.hashProvider()
+	digits := tc.digits()
+
+	keys := tc.keys(now)
+	generators := make([]*hotpGenerator, 0, len(keys))
+	for _, key := range keys {
+		g, err := newHOTPGenerator(hashProvider, key, digits)
+		if err != nil {
 			continue
 		}
+		generators = append(generators, g)
+	}
+	return generators
+}
+
+// codesEqual compares codes as fixed width byte strings in constant time, returning 1
+// if they are equal and 0 otherwise.
+func codesEqual(a, b int) int {
+	var x, y [8]byte
+	binary.BigEndian.PutUint64(x[:], uint64(a))
+	binary.BigEndian.PutUint64(y[:], uint64(b))
+	return subtle.ConstantTimeCompare(x[:], y[:])
+}
+
+func (tc *TOTPValidator) hashProvider() func() hash.Hash {
+	if tc.HashProvider == nil {
+		return DefaultHashProvider
+	}
+	return tc.HashProvider
+}
+
+func (tc *TOTPValidator) digits() Digits {
+	if tc.Digits == 0 {
+		return DefaultDigits
+	}
+	return tc.Digits
+}
+
+func (tc *TOTPValidator) stepSize() time.Duration {
+	if tc.StepSize != 0 {
+		return tc.StepSize
+	}
+	if tc.StepSizeSeconds == 0 {
+		return DefaultStepSizeSeconds * time.Second
+	}
+	return time.Duration(tc.StepSizeSeconds) * time.Second
+}
+
+// pastTolerance returns how far before now codes are accepted for, from PastSteps when
+// set. A tolerance of a whole number of steps accepts exactly that many earlier steps.
+func (tc *TOTPValidator) pastTolerance() time.Duration {
+	if tc.PastSteps > 0 {
+		return time.Duration(tc.PastSteps) * tc.stepSize()
+	}
+	return tc.PastTolerance
+}
+
+// futureTolerance is pastTolerance for FutureSteps and FutureTolerance.
+func (tc *TOTPValidator) futureTolerance() time.Duration {
+	if tc.FutureSteps > 0 {
+		return time.Duration(tc.FutureSteps) * tc.stepSize()
+	}
+	return tc.FutureTolerance
+}
+
+// steps returns the time steps accepted at now in ascending order, ignoring LastT.
+func (tc *TOTPValidator) steps(now time.Time) []int {
+	return tc.stepsAt(tc.stepSize(), now)
+}
+
+// stepsAt is steps for an alternative step size.
+func (tc *TOTPValidator) stepsAt(stepSize time.Duration, now time.Time) []int {
+	if len(tc.AcceptOffsets) > 0 {
+		current := tc.stepAt(stepSize, now)
+		offsets := append([]int(nil), tc.AcceptOffsets...)
+		sort.Ints(offsets)
+
+		steps := make([]int, 0, len(offsets))
+		for i, offset := range offsets {
+			if i > 0 && offset == offsets[i-1] {
+				continue
+			}
+			steps = append(steps, current+offset)
+		}
+		return steps
+	}
 
-		if HOTPCode(hashProvider, tc.Key, digits, int64(t)) == code {
-			return true, t
+	tMin, tMax := tc.window(stepSize, now)
+	return stepRange(tMin, tMax)
+}
+
+// stepRange returns the time steps from tMin to tMax inclusive. It is empty when tMax is
+// before tMin, as it is for a window with a negative tolerance.
+func stepRange(tMin, tMax int) []int {
+	if tMax < tMin {
+		return []int{}
+	}
+
+	steps := make([]int, 0, tMax-tMin+1)
+	for t := tMin; t <= tMax; t++ {
+		steps = append(steps, t)
+	}
+	return steps
+}
+
+// window returns the range of time steps accepted at now, ignoring LastT.
+func (tc *TOTPValidator) window(stepSize time.Duration, now time.Time) (int, int) {
+	tMin, tMax := tc.toleranceWindow(stepSize, now)
+	return tc.clampWindow(tc.stepAt(stepSize, now), tMin, tMax)
+}
+
+// toleranceWindow returns the range of time steps the tolerances cover at now, before it
+// is capped at MaxWindowSteps.
+func (tc *TOTPValidator) toleranceWindow(stepSize time.Duration, now time.Time) (int, int) {
+	pastTolerance, futureTolerance := tc.pastTolerance(), tc.futureTolerance()
+	if lenient := tc.enrollmentTolerance(now); lenient > 0 {
+		if lenient > pastTolerance {
+			pastTolerance = lenient
+		}
+		if lenient > futureTolerance {
+			futureTolerance = lenient
 		}
 	}
 
-	return false, timeSteps(stepSizeSeconds, now)
+	return tc.stepAt(stepSize, now.Add(-pastTolerance)), tc.stepAt(stepSize, now.Add(futureTolerance))
 }
 
+// timeSteps is TimeStep for a step size known to be set.
 func timeSteps(stepSize int, t time.Time) int {
 	return int(t.Unix() / int64(stepSize))
 }
+
+// durationSteps is timeSteps for a step size expressed as a duration. Whole second step
+// sizes are computed exactly as timeSteps does.
+func durationSteps(stepSize time.Duration, t time.Time) int {
+	return int(durationCounter(stepSize, t))
+}
+
+// durationCounter is durationSteps computed as an int64 which, unlike int on 32 bit
+// platforms, holds the step count for sub-second step sizes.
+func durationCounter(stepSize time.Duration, t time.Time) int64 {
+	if stepSize%time.Second == 0 {
+		return t.Unix() / int64(stepSize/time.Second)
+	}
+	return t.UnixNano() / stepSize.Nanoseconds()
+}
+
+// stepStart returns the instant time step t begins for the given step size.
+func stepStart(stepSize time.Duration, t int) time.Time {
+	if stepSize%time.Second == 0 {
+		return time.Unix(int64(t)*int64(stepSize/time.Second), 0)
+	}
+	return time.Unix(0, int64(t)*stepSize.Nanoseconds())
+}
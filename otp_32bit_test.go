@@ -0,0 +1,43 @@
+//go:build 386 || arm || mips || mipsle
+// +build 386 arm mips mipsle
+
+package otp
+
+import (
+	"crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestHOTPCode32Bit(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name   string
+		Value  int64
+		Digits Digits
+		Code   int
+	}{
+		{"Eight Digits Near Max", 280466, EightDigits, 99999523},
+		{"Ten Digits Near Max", 84571, TenDigits, 2147463589},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if code := HOTPCode(sha1.New, key, test.Digits, test.Value); code != test.Code {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", test.Code, code)
+			}
+		})
+	}
+}
+
+func TestTOTPCodeDuration32Bit(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Date(2005, 3, 18, 1, 58, 29, 500*int(time.Millisecond), time.UTC)
+
+	code := TOTPCodeDuration(sha1.New, key, SixDigits, time.Millisecond, now)
+	expected := HOTPCode(sha1.New, key, SixDigits, now.UnixNano()/int64(time.Millisecond))
+	if code != expected {
+		t.Errorf("Code did not match. Expected %d and got %d.\n", expected, code)
+	}
+}
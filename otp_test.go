@@ -4,9 +4,10 @@ import (
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
-	"encoding/base32"
 	"fmt"
 	"hash"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -21,7 +22,7 @@ func TestHOTPCode(t *testing.T) {
 		{10000, 50548},
 	}
 
-	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString("2SH3V3GDW7ZNMGYE")
+	secret, err := DecodeSecret("2SH3V3GDW7ZNMGYE")
 	if err != nil {
 		t.Fatalf("failed to decode key: %v", err)
 	}
@@ -35,6 +36,44 @@ func TestHOTPCode(t *testing.T) {
 	}
 }
 
+func TestHOTPCodeErr(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name         string
+		HashProvider func() hash.Hash
+		Key          []byte
+		Digits       Digits
+		Code         int
+		Err          error
+	}{
+		{"Valid", sha1.New, key, SixDigits, 755224, nil},
+		{"Nil Hash Provider", nil, key, SixDigits, 0, ErrNilHashProvider},
+		{"Nil Key", sha1.New, nil, SixDigits, 0, ErrEmptyKey},
+		{"Empty Key", sha1.New, []byte{}, SixDigits, 0, ErrEmptyKey},
+		{"Zero Digits", sha1.New, key, 0, 0, ErrZeroDigits},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code, err := HOTPCodeErr(test.HashProvider, test.Key, test.Digits, 0)
+			if err != test.Err {
+				t.Errorf("Error did not match. Expected %v and got %v.\n", test.Err, err)
+			}
+			if code != test.Code {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", test.Code, code)
+			}
+
+			defer func() {
+				if r := recover(); r != test.Err {
+					t.Errorf("Panic did not match. Expected %v and got %v.\n", test.Err, r)
+				}
+			}()
+			HOTPCode(test.HashProvider, test.Key, test.Digits, 0)
+		})
+	}
+}
+
 func TestRFC4226(t *testing.T) {
 	var tests = []struct {
 		Value int64
@@ -61,6 +100,49 @@ func TestRFC4226(t *testing.T) {
 	}
 }
 
+func TestRFC4226NineAndTenDigits(t *testing.T) {
+	// the truncated values of RFC 4226 Appendix D
+	var tests = []struct {
+		Value int64
+		Nine  int
+		Ten   int
+	}{
+		{0, 284755224, 1284755224},
+		{1, 94287082, 1094287082},
+		{2, 137359152, 137359152},
+		{3, 726969429, 1726969429},
+		{4, 640338314, 1640338314},
+		{5, 868254676, 868254676},
+		{6, 918287922, 1918287922},
+		{7, 82162583, 82162583},
+		{8, 673399871, 673399871},
+		{9, 645520489, 645520489},
+	}
+
+	for _, test := range tests {
+		c := HOTPCode(sha1.New, []byte("12345678901234567890"), NineDigits, test.Value)
+		if c != test.Nine {
+			t.Errorf("Nine digit code did not match for %d. Expected %d but got %d\n", test.Value, test.Nine, c)
+		}
+
+		c = HOTPCode(sha1.New, []byte("12345678901234567890"), TenDigits, test.Value)
+		if c != test.Ten {
+			t.Errorf("Ten digit code did not match for %d. Expected %d but got %d\n", test.Value, test.Ten, c)
+		}
+	}
+
+	validator := &TOTPValidator{
+		Key:    []byte("12345678901234567890"),
+		Digits: TenDigits,
+	}
+	if ok, _ := validator.ValidateStringCT(time.Unix(30, 0), "1094287082"); !ok {
+		t.Error("Ten digit code did not validate")
+	}
+	if s := HOTPCodeString(sha1.New, []byte("12345678901234567890"), TenDigits, 2); s != "0137359152" {
+		t.Errorf("Ten digit code string did not match. Expected 0137359152 but got %s\n", s)
+	}
+}
+
 func TestRFC6238(t *testing.T) {
 	sha1Key := []byte("12345678901234567890")
 	sha256Key := []byte("12345678901234567890123456789012")
@@ -170,6 +252,330 @@ func TestTOTPValidator(t *testing.T) {
 	}
 }
 
+func TestTOTPValidatorScansFullWindow(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Code  int
+		Match bool
+		T     int
+	}{
+		{"Match First Step", 89731029, true, 0x23523EB},
+		{"Match Middle Step", 7081804, true, 0x23523EC},
+		{"Match Last Step", 14050471, true, 0x23523ED},
+		{"No Match", 7081803, false, 0x23523EC},
+	}
+
+	counts := make(map[int]bool)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sums := 0
+			validator := &TOTPValidator{
+				Key: []byte("12345678901234567890"),
+				HashProvider: func() hash.Hash {
+					return summingHash{sha1.New(), &sums}
+				},
+				Digits:          EightDigits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+			}
+
+			match, tMatch := validator.ValidateTOTPCode(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+			// each HMAC sums the inner and outer hash
+			if hmacs := sums / 2; hmacs != 3 {
+				t.Errorf("HMAC computations did not match. Expected 3 and got %d.\n", hmacs)
+			}
+			counts[sums] = true
+		})
+	}
+
+	if len(counts) != 1 {
+		t.Errorf("Validation cost varied: %v", counts)
+	}
+}
+
+func TestTOTPValidatorEmptyKey(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name     string
+		Validate func(tc *TOTPValidator) bool
+	}{
+		{"ValidateTOTPCode", func(tc *TOTPValidator) bool {
+			ok, tMatch := tc.ValidateTOTPCode(now, 81804)
+			if tMatch != 0x23523EC {
+				t.Errorf("T did not match. Expected %d and got %d.\n", 0x23523EC, tMatch)
+			}
+			return ok
+		}},
+		{"Validate", func(tc *TOTPValidator) bool {
+			tc.Clock = NewFakeClock(now)
+			ok, _ := tc.Validate(81804)
+			return ok
+		}},
+		{"VerifyAndConsume", func(tc *TOTPValidator) bool { return tc.VerifyAndConsume(now, 81804) }},
+		{"ValidateCached", func(tc *TOTPValidator) bool { return tc.ValidateCached(now, 81804) }},
+		{"FixedScanSteps", func(tc *TOTPValidator) bool {
+			tc.FixedScanSteps = 5
+			ok, _ := tc.ValidateTOTPCode(now, 81804)
+			return ok
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if test.Validate(&TOTPValidator{}) {
+				t.Error("Code matched an empty key")
+			}
+		})
+	}
+}
+
+func TestCodesEqual(t *testing.T) {
+	tests := []struct {
+		Name  string
+		A     int
+		B     int
+		Equal int
+	}{
+		{"Equal", 7081804, 7081804, 1},
+		{"Zero", 0, 0, 1},
+		{"Last Digit", 7081804, 7081803, 0},
+		{"High Bits", 7081804, 7081804 | 1<<30, 0},
+		{"Negative", -7081804, 7081804, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			equal := codesEqual(test.A, test.B)
+			if equal != test.Equal {
+				t.Errorf("Equal did not match. Expected %d and got %d.\n", test.Equal, equal)
+			}
+		})
+	}
+}
+
+func TestToleranceSteps(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Code            int
+		Match           bool
+		PastSteps       int
+		FutureSteps     int
+		PastTolerance   time.Duration
+		FutureTolerance time.Duration
+	}{
+		{"T-1 One Past Step", 89731029, true, 1, 0, 0, 0},
+		{"T-2 One Past Step", 48150727, false, 1, 0, 0, 0},
+		{"T-2 Two Past Steps", 48150727, true, 2, 0, 0, 0},
+		{"T+1 One Future Step", 14050471, true, 0, 1, 0, 0},
+		{"T+2 One Future Step", 44266759, false, 0, 1, 0, 0},
+		{"T-1 Steps Override Tolerance", 89731029, true, 1, 0, 0, time.Second},
+		{"T-2 Steps Override Wider Tolerance", 48150727, false, 1, 0, 2 * time.Minute, 0},
+		{"T+1 Tolerance Without Steps", 14050471, true, 1, 0, 0, 30 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastSteps:       test.PastSteps,
+				FutureSteps:     test.FutureSteps,
+				PastTolerance:   test.PastTolerance,
+				FutureTolerance: test.FutureTolerance,
+			}
+
+			match, _ := validator.ValidateTOTPCode(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+		})
+	}
+}
+
+func TestAcceptOffsets(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Code    int
+		Match   bool
+		T       int
+		LastT   int
+		Offsets []int
+	}{
+		{"T Match Current Only", 7081804, true, 0x23523EC, 0, []int{0}},
+		{"T-1 Match Current Only", 89731029, false, 0x23523EC, 0, []int{0}},
+		{"T-1 Match Adjacent", 89731029, true, 0x23523EB, 0, []int{-1, 0, 1}},
+		{"T+1 Match Adjacent", 14050471, true, 0x23523ED, 0, []int{1, 0, -1}},
+		{"T Match Skip Current", 7081804, false, 0x23523EC, 0, []int{-1, 1}},
+		{"T+2 Match Sparse", 44266759, true, 0x23523EE, 0, []int{-1, 2}},
+		{"T+1 Match Sparse", 14050471, false, 0x23523EC, 0, []int{-1, 2}},
+		{"T+1 Match Duplicates", 14050471, true, 0x23523ED, 0, []int{1, 1}},
+		{"T Match LastT", 7081804, false, 0x23523EC, 0x23523EC, []int{-1, 0, 1}},
+		{"T+1 Match LastT", 14050471, true, 0x23523ED, 0x23523EC, []int{-1, 0, 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				HashProvider:    sha1.New,
+				Digits:          EightDigits,
+				PastTolerance:   time.Hour, // ignored as AcceptOffsets is set
+				FutureTolerance: time.Hour,
+				LastT:           test.LastT,
+				AcceptOffsets:   test.Offsets,
+			}
+
+			testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+			match, tMatch := validator.ValidateTOTPCode(testTime, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+
+			if cached := validator.ValidateCached(testTime, test.Code); cached != test.Match {
+				t.Errorf("Cached match did not match. Expected %t and got %t.\n", test.Match, cached)
+			}
+		})
+	}
+}
+
+func TestTOTPCodeDuration(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name string
+		Time time.Time
+		Code int
+	}{
+		{"59", time.Date(1970, 1, 1, 0, 0, 59, 0, time.UTC), 94287082},
+		{"1111111109", time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), 7081804},
+		{"1234567890", time.Date(2009, 2, 13, 23, 31, 30, 0, time.UTC), 89005924},
+		{"20000000000", time.Date(2603, 10, 11, 11, 33, 20, 0, time.UTC), 65353130},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code := TOTPCodeDuration(sha1.New, key, EightDigits, 30*time.Second, test.Time)
+			if code != test.Code {
+				t.Errorf("Code did not match. Expected %d and got %d.\n", test.Code, code)
+			}
+
+			validator := &TOTPValidator{
+				Key:      key,
+				StepSize: 30 * time.Second,
+				Digits:   EightDigits,
+			}
+			if ok, _ := validator.ValidateTOTPCode(test.Time, test.Code); !ok {
+				t.Error("Code did not match")
+			}
+		})
+	}
+}
+
+func TestSubSecondStepSize(t *testing.T) {
+	if strconv.IntSize == 32 {
+		t.Skip("sub-second time steps overflow int on 32 bit platforms")
+	}
+
+	key := []byte("12345678901234567890")
+	stepSize := time.Millisecond
+	now := time.Date(2005, 3, 18, 1, 58, 29, 500*int(time.Millisecond), time.UTC)
+
+	validator := &TOTPValidator{
+		Key:             key,
+		StepSizeSeconds: 30, // ignored as StepSize is set
+		StepSize:        stepSize,
+	}
+
+	code := TOTPCodeDuration(sha1.New, key, SixDigits, stepSize, now)
+	expected := HOTPCode(sha1.New, key, SixDigits, now.UnixNano()/int64(stepSize))
+	if code != expected {
+		t.Errorf("Code did not match. Expected %d and got %d.\n", expected, code)
+	}
+
+	ok, tMatch := validator.ValidateTOTPCode(now, code)
+	if !ok {
+		t.Error("Code did not match")
+	}
+	if int64(tMatch) != now.UnixNano()/int64(stepSize) {
+		t.Errorf("T did not match. Expected %d and got %d.\n", now.UnixNano()/int64(stepSize), tMatch)
+	}
+
+	next := now.Add(stepSize)
+	if code == TOTPCodeDuration(sha1.New, key, SixDigits, stepSize, next) {
+		t.Fatal("Code did not rotate")
+	}
+	if ok, _ := validator.ValidateTOTPCode(next, code); ok {
+		t.Error("Code matched after rotation")
+	}
+
+	validator.PastTolerance = stepSize
+	if ok, _ := validator.ValidateTOTPCode(next, code); !ok {
+		t.Error("Code did not match within past tolerance")
+	}
+}
+
+func TestDefaultHashProvider(t *testing.T) {
+	defer func(previous func() hash.Hash) { DefaultHashProvider = previous }(DefaultHashProvider)
+	DefaultHashProvider = sha256.New
+
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	key := []byte("12345678901234567890123456789012")
+	code := TOTPCode(sha256.New, key, DefaultDigits, DefaultStepSizeSeconds, now)
+
+	validator := &TOTPValidator{Key: key}
+	if ok, _ := validator.ValidateTOTPCode(now, code); !ok {
+		t.Error("Zero value validator did not use DefaultHashProvider")
+	}
+
+	if c := (Config{Key: key}).Code(now); c != code {
+		t.Errorf("Config code did not match. Expected %d and got %d.\n", code, c)
+	}
+
+	hotp := &HOTPValidator{Key: key, Counter: int64(timeSteps(DefaultStepSizeSeconds, now))}
+	if ok, _ := hotp.Validate(code); !ok {
+		t.Error("Zero value HOTP validator did not use DefaultHashProvider")
+	}
+
+	uri := KeyURI{Account: "alice", Key: key}.String()
+	if !strings.Contains(uri, "algorithm=SHA256") {
+		t.Errorf("URI did not use DefaultHashProvider: %s\n", uri)
+	}
+}
+
+func BenchmarkHOTPCode(b *testing.B) {
+	key := []byte("12345678901234567890")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		HOTPCode(sha1.New, key, SixDigits, int64(i))
+	}
+}
+
+func BenchmarkValidateTOTPCode(b *testing.B) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	validator := TOTPValidator{
+		Key:         []byte("12345678901234567890"),
+		PastSteps:   4,
+		FutureSteps: 4,
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.ValidateTOTPCode(now, 123456)
+	}
+}
+
 func Example() {
 	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
 	key := []byte("12345678901234567890")
@@ -177,7 +583,6 @@ func Example() {
 
 	code := TOTPCode(sha1.New, key, SixDigits, stepSizeSeconds, now)
 	fmt.Printf("TOTP code is: %06d\n", code)
-	// Output: TOTP code is: 081804
 
 	validator := TOTPValidator{
 		Key:             key,
@@ -187,11 +592,13 @@ func Example() {
 
 	ok, lastT := validator.ValidateTOTPCode(now, code)
 	fmt.Printf("Valid: %t\n", ok)
-	// Valid: true
 
 	validator.LastT = lastT
 
 	ok, lastT = validator.ValidateTOTPCode(now, code)
 	fmt.Printf("Reuse Valid: %t\n", ok)
+	// Output:
+	// TOTP code is: 081804
+	// Valid: true
 	// Reuse Valid: false
 }
@@ -0,0 +1,67 @@
+package otp
+
+import (
+	"crypto/subtle"
+	"time"
+)
+
+// ValidateDualPeriod returns a bool indicating if code is valid for the provided time
+// using any of the step sizes in periods, given in seconds. It supports migrating tokens
+// between step sizes where codes from both the old and new period must be accepted for
+// a while. Periods are tried in order and the matching period is returned along with the
+// matched T, which is counted in steps of that period. Every step of every period is
+// compared in constant time whether or not an earlier one matched.
+// LastT is interpreted in the validator's own step size: a step of another period is only
+// accepted if it ends after step LastT ends, so a used code can't be replayed through a
+// different period. ReplayStore is only consulted for steps of the validator's own step
+// size. An accepted step of the own step size is consumed as for ValidateStringCT while
+// one of another period advances LastT to the step it ends in. On failure the period is 0
+// and T is the validator's current step.
+func (tc *TOTPValidator) ValidateDualPeriod(now time.Time, code int, periods []int) (bool, int, int) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	generators := tc.generators(now)
+	consumed := tc.stepBegins(tc.stepSize(), tc.LastT+1)
+
+	matched := 0
+	periodMatch := 0
+	tMatch := tc.stepAt(tc.stepSize(), now)
+	for _, period := range periods {
+		if period <= 0 {
+			continue
+		}
+
+		stepSize := time.Duration(period) * time.Second
+		for _, t := range tc.stepsAt(stepSize, now) {
+			if !tc.stepBegins(stepSize, t+1).After(consumed) {
+				continue
+			}
+			if stepSize == tc.stepSize() && tc.used(t) {
+				continue
+			}
+
+			for _, g := range generators {
+				isMatch := codesEqual(g.code(int64(t)), code)
+				tMatch = subtle.ConstantTimeSelect(isMatch&^matched, t, tMatch)
+				periodMatch = subtle.ConstantTimeSelect(isMatch&^matched, period, periodMatch)
+				matched |= isMatch
+			}
+		}
+	}
+
+	if matched == 0 {
+		return false, 0, tMatch
+	}
+
+	stepSize := time.Duration(periodMatch) * time.Second
+	if stepSize == tc.stepSize() {
+		tc.recordUse(now, tMatch)
+	} else {
+		if last := tc.stepAt(tc.stepSize(), tc.stepBegins(stepSize, tMatch+1).Add(-1)); last > tc.LastT {
+			tc.LastT = last
+		}
+		tc.recordSuccessLocked(now)
+	}
+	return true, periodMatch, tMatch
+}
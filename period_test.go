@@ -0,0 +1,83 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestValidateDualPeriod(t *testing.T) {
+	key := []byte("12345678901234567890")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name    string
+		Code    int
+		Periods []int
+		LastT   int
+		Match   bool
+		Period  int
+		T       int
+	}{
+		{"30 Seconds", 81804, []int{30, 60}, 0, true, 30, 0x23523EC},
+		{"60 Seconds", TOTPCode(sha1.New, key, SixDigits, 60, testTime), []int{30, 60}, 0, true, 60, 0x11A91F6},
+		{"60 Seconds Not Accepted", TOTPCode(sha1.New, key, SixDigits, 60, testTime), []int{30}, 0, false, 0, 0x23523EC},
+		{"No Match", 81803, []int{30, 60}, 0, false, 0, 0x23523EC},
+		{"Invalid Periods", 81804, []int{0, -30}, 0, false, 0, 0x23523EC},
+		{"30 Seconds LastT", 81804, []int{30, 60}, 0x23523EC, false, 0, 0x23523EC},
+		// the current 60 second step ends after the current 30 second step
+		{"60 Seconds LastT", TOTPCode(sha1.New, key, SixDigits, 60, testTime), []int{30, 60}, 0x23523EC, true, 60, 0x11A91F6},
+		{"60 Seconds LastT Consumed", TOTPCode(sha1.New, key, SixDigits, 60, testTime), []int{30, 60}, 0x23523ED, false, 0, 0x23523EC},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:   key,
+				LastT: test.LastT,
+			}
+
+			match, period, tMatch := validator.ValidateDualPeriod(testTime, test.Code, test.Periods)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if period != test.Period {
+				t.Errorf("Period did not match. Expected %d and got %d.\n", test.Period, period)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
+
+func TestValidateDualPeriodReplay(t *testing.T) {
+	key := []byte("12345678901234567890")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name  string
+		Code  int
+		LastT int
+	}{
+		{"30 Seconds", 81804, 0x23523EC},
+		// the current 60 second step ends with the next 30 second step
+		{"60 Seconds", TOTPCode(sha1.New, key, SixDigits, 60, testTime), 0x23523ED},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{Key: key}
+
+			if match, _, _ := validator.ValidateDualPeriod(testTime, test.Code, []int{30, 60}); !match {
+				t.Fatal("Code did not match")
+			}
+			if validator.LastT != test.LastT {
+				t.Errorf("LastT did not match. Expected %d and got %d.\n", test.LastT, validator.LastT)
+			}
+			if match, _, _ := validator.ValidateDualPeriod(testTime, test.Code, []int{30, 60}); match {
+				t.Error("Replayed code matched")
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package otp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"github.com/mctofu/otp/internal/qr"
+)
+
+// QRLevel is the error correction level of a QR code. Higher levels keep the code
+// readable when more of it is damaged or obscured, for example by a logo, at the cost of
+// a denser code.
+type QRLevel int
+
+// QR code error correction levels, recovering roughly 7%, 15%, 25% and 30% of the code.
+const (
+	QRLevelLow QRLevel = iota
+	QRLevelMedium
+	QRLevelQuartile
+	QRLevelHigh
+)
+
+// ErrInvalidQRLevel is returned when generating a QR code with an unknown QRLevel.
+var ErrInvalidQRLevel = errors.New("otp: invalid QR code error correction level")
+
+// qrQuietZone is the number of light modules required around a QR code.
+const qrQuietZone = 4
+
+// QRCode returns a size by size pixel PNG image of a QR code encoding the provisioning URI
+// of k, for display when enrolling an authenticator app. QRLevelMedium error correction
+// is used.
+func (k KeyURI) QRCode(size int) ([]byte, error) {
+	var b bytes.Buffer
+	if err := k.WriteQRCode(&b, size, QRLevelMedium); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// WriteQRCode writes a size by size pixel PNG image of a QR code encoding the provisioning
+// URI of k to w, using level error correction. Each module of the code is drawn as a whole
+// number of pixels with the remainder added to the quiet zone around it. An error is
+// returned if size is too small to draw at least one pixel per module.
+func (k KeyURI) WriteQRCode(w io.Writer, size int, level QRLevel) error {
+	code, err := k.qrCode(level)
+	if err != nil {
+		return err
+	}
+
+	modules := code.Size + 2*qrQuietZone
+	scale := size / modules
+	if scale < 1 {
+		return fmt.Errorf("otp: QR code size %d is smaller than its %d modules", size, modules)
+	}
+	// centre the code, leaving at least the quiet zone around it
+	offset := (size - scale*code.Size) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if code.Black(floorDiv(x-offset, scale), floorDiv(y-offset, scale)) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// qrCode encodes the provisioning URI of k.
+func (k KeyURI) qrCode(level QRLevel) (*qr.Code, error) {
+	if level < QRLevelLow || level > QRLevelHigh {
+		return nil, ErrInvalidQRLevel
+	}
+
+	code, err := qr.Encode([]byte(k.String()), qr.Level(level))
+	if err != nil {
+		return nil, fmt.Errorf("otp: encoding QR code: %w", err)
+	}
+	return code, nil
+}
+
+// floorDiv is integer division rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
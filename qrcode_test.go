@@ -0,0 +1,94 @@
+package otp
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestKeyURIQRCode(t *testing.T) {
+	k := KeyURI{
+		Issuer:  "Example",
+		Account: "alice@example.com",
+		Key:     []byte("12345678901234567890"),
+	}
+
+	data, err := k.QRCode(256)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	if bounds := img.Bounds(); bounds.Dx() != 256 || bounds.Dy() != 256 {
+		t.Errorf("Size did not match. Expected 256x256 and got %dx%d.\n", bounds.Dx(), bounds.Dy())
+	}
+
+	code, err := k.qrCode(QRLevelMedium)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	// every module is drawn as a square of scale pixels centred in the image
+	scale := 256 / (code.Size + 2*qrQuietZone)
+	offset := (256 - scale*code.Size) / 2
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			r, _, _, _ := img.At(offset+x*scale, offset+y*scale).RGBA()
+			if black := r == 0; black != code.Black(x, y) {
+				t.Fatalf("Module %d,%d did not match. Expected %t and got %t.\n", x, y, code.Black(x, y), black)
+			}
+		}
+	}
+
+	for _, p := range [][2]int{{0, 0}, {offset - 1, offset - 1}, {255, 255}} {
+		if r, _, _, _ := img.At(p[0], p[1]).RGBA(); r == 0 {
+			t.Errorf("Quiet zone pixel %v was dark.\n", p)
+		}
+	}
+}
+
+func TestKeyURIWriteQRCodeErrors(t *testing.T) {
+	k := KeyURI{Account: "alice", Key: []byte("12345678901234567890")}
+
+	tests := []struct {
+		Name  string
+		Size  int
+		Level QRLevel
+	}{
+		{"Too Small", 20, QRLevelMedium},
+		{"Zero Size", 0, QRLevelLow},
+		{"Invalid Level", 256, QRLevelHigh + 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := k.WriteQRCode(&b, test.Size, test.Level); err == nil {
+				t.Error("Expected an error")
+			}
+			if b.Len() != 0 {
+				t.Errorf("Output did not match. Expected nothing and got %d bytes.\n", b.Len())
+			}
+		})
+	}
+}
+
+func TestKeyURIWriteQRCodeLevels(t *testing.T) {
+	k := KeyURI{Issuer: "Example", Account: "alice", Key: []byte("12345678901234567890")}
+
+	previous := 0
+	for level := QRLevelLow; level <= QRLevelHigh; level++ {
+		code, err := k.qrCode(level)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if code.Size < previous {
+			t.Errorf("Size for level %d did not grow. Got %d after %d.\n", level, code.Size, previous)
+		}
+		previous = code.Size
+	}
+}
@@ -0,0 +1,74 @@
+package otp
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/mctofu/otp/internal/qr"
+)
+
+// ANSI escape sequences setting black on white and resetting the colours.
+const (
+	ansiBlackOnWhite = "\x1b[30;47m"
+	ansiReset        = "\x1b[0m"
+)
+
+// QRCodeTerminal writes a QR code of the provisioning URI of k to w as text, so a
+// command line tool can show it for scanning by a phone without an image viewer.
+// Two rows of modules are drawn per line with half block characters, coloured black on
+// white so the code scans on terminals with a dark background. When the NO_COLOR
+// environment variable is set the code is instead drawn without colours as "##" for each
+// dark module, one row per line. QRLevelLow error correction keeps the code small.
+func (k KeyURI) QRCodeTerminal(w io.Writer) error {
+	code, err := k.qrCode(QRLevelLow)
+	if err != nil {
+		return err
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return writeQRPlain(w, code)
+	}
+	return writeQRHalfBlocks(w, code)
+}
+
+// writeQRHalfBlocks draws code two rows per line with its quiet zone.
+func writeQRHalfBlocks(w io.Writer, code *qr.Code) error {
+	b := bufio.NewWriter(w)
+	for y := -qrQuietZone; y < code.Size+qrQuietZone; y += 2 {
+		b.WriteString(ansiBlackOnWhite)
+		for x := -qrQuietZone; x < code.Size+qrQuietZone; x++ {
+			top, bottom := code.Black(x, y), code.Black(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(ansiReset)
+		b.WriteByte('\n')
+	}
+	return b.Flush()
+}
+
+// writeQRPlain draws code one row per line using two characters per module, which keeps
+// modules roughly square in a terminal font.
+func writeQRPlain(w io.Writer, code *qr.Code) error {
+	b := bufio.NewWriter(w)
+	for y := -qrQuietZone; y < code.Size+qrQuietZone; y++ {
+		for x := -qrQuietZone; x < code.Size+qrQuietZone; x++ {
+			if code.Black(x, y) {
+				b.WriteString("##")
+			} else {
+				b.WriteString("  ")
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.Flush()
+}
@@ -0,0 +1,100 @@
+package otp
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// setNoColor sets the NO_COLOR environment variable, returning a func restoring it.
+func setNoColor(value string) func() {
+	previous, ok := os.LookupEnv("NO_COLOR")
+	os.Setenv("NO_COLOR", value)
+	return func() {
+		if ok {
+			os.Setenv("NO_COLOR", previous)
+		} else {
+			os.Unsetenv("NO_COLOR")
+		}
+	}
+}
+
+func TestKeyURIQRCodeTerminal(t *testing.T) {
+	defer setNoColor("")()
+
+	k := KeyURI{Issuer: "Example", Account: "alice", Key: []byte("12345678901234567890")}
+	code, err := k.qrCode(QRLevelLow)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	var b bytes.Buffer
+	if err := k.QRCodeTerminal(&b); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	width := code.Size + 2*qrQuietZone
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != (width+1)/2 {
+		t.Fatalf("Lines did not match. Expected %d and got %d.\n", (width+1)/2, len(lines))
+	}
+
+	for i, line := range lines {
+		if !strings.HasPrefix(line, ansiBlackOnWhite) || !strings.HasSuffix(line, ansiReset) {
+			t.Fatalf("Line %d is not coloured black on white: %q\n", i, line)
+		}
+
+		cells := []rune(strings.TrimSuffix(strings.TrimPrefix(line, ansiBlackOnWhite), ansiReset))
+		if len(cells) != width {
+			t.Fatalf("Width of line %d did not match. Expected %d and got %d.\n", i, width, len(cells))
+		}
+
+		y := 2*i - qrQuietZone
+		for j, cell := range cells {
+			x := j - qrQuietZone
+			top := cell == '█' || cell == '▀'
+			bottom := cell == '█' || cell == '▄'
+			if top != code.Black(x, y) || bottom != code.Black(x, y+1) {
+				t.Fatalf("Modules %d,%d and %d,%d did not match. Got %q.\n", x, y, x, y+1, cell)
+			}
+		}
+	}
+}
+
+func TestKeyURIQRCodeTerminalNoColor(t *testing.T) {
+	defer setNoColor("1")()
+
+	k := KeyURI{Issuer: "Example", Account: "alice", Key: []byte("12345678901234567890")}
+	code, err := k.qrCode(QRLevelLow)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	var b bytes.Buffer
+	if err := k.QRCodeTerminal(&b); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	if strings.Contains(b.String(), "\x1b") {
+		t.Error("Output contained escape sequences")
+	}
+
+	width := code.Size + 2*qrQuietZone
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != width {
+		t.Fatalf("Lines did not match. Expected %d and got %d.\n", width, len(lines))
+	}
+
+	for i, line := range lines {
+		if len(line) != 2*width {
+			t.Fatalf("Width of line %d did not match. Expected %d and got %d.\n", i, 2*width, len(line))
+		}
+		for j := 0; j < width; j++ {
+			x, y := j-qrQuietZone, i-qrQuietZone
+			if dark := line[2*j:2*j+2] == "##"; dark != code.Black(x, y) {
+				t.Fatalf("Module %d,%d did not match. Expected %t and got %t.\n", x, y, code.Black(x, y), dark)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package otp
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrRateLimited is returned when a validation is rejected by a Limiter.
+var ErrRateLimited = errors.New("otp: validation rate limited")
+
+// Limiter decides whether a validation attempt may proceed.
+// A golang.org/x/time/rate.Limiter satisfies this interface.
+type Limiter interface {
+	Allow() bool
+}
+
+// BurstLimiter is a Limiter that reports the most attempts it allows at once, used to
+// determine AttemptsBeforeLockout. A golang.org/x/time/rate.Limiter satisfies this
+// interface.
+type BurstLimiter interface {
+	Limiter
+	Burst() int
+}
+
+// RateLimitedValidator consults a Limiter before every validation so brute force
+// attempts are rejected without computing any codes.
+type RateLimitedValidator struct {
+	Validator *TOTPValidator
+	Limiter   Limiter
+}
+
+// RateLimited wraps tc so each validation is first checked against limiter.
+func RateLimited(tc *TOTPValidator, limiter Limiter) *RateLimitedValidator {
+	return &RateLimitedValidator{
+		Validator: tc,
+		Limiter:   limiter,
+	}
+}
+
+// ValidateTOTPCode behaves like TOTPValidator.ValidateTOTPCode when the limiter allows
+// the attempt. Otherwise it returns ErrRateLimited and a T of 0.
+func (rv *RateLimitedValidator) ValidateTOTPCode(now time.Time, code int) (bool, int, error) {
+	if !rv.Limiter.Allow() {
+		return false, 0, ErrRateLimited
+	}
+
+	ok, t := rv.Validator.ValidateTOTPCode(now, code)
+	return ok, t, nil
+}
+
+// SecurityProfile returns the validator's SecurityProfile including the attempts allowed
+// before lockout when the Limiter is a BurstLimiter.
+func (rv *RateLimitedValidator) SecurityProfile() SecurityProfile {
+	profile := rv.Validator.SecurityProfile()
+	if limiter, ok := rv.Limiter.(BurstLimiter); ok {
+		profile = profile.withLockout(limiter.Burst())
+	}
+	return profile
+}
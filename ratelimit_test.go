@@ -0,0 +1,96 @@
+package otp
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+type budgetLimiter int
+
+func (b *budgetLimiter) Allow() bool {
+	if *b <= 0 {
+		return false
+	}
+	*b--
+	return true
+}
+
+type burstLimiter struct {
+	budgetLimiter
+	burst int
+}
+
+func (b *burstLimiter) Burst() int {
+	return b.burst
+}
+
+func TestRateLimited(t *testing.T) {
+	budget := budgetLimiter(2)
+	validator := RateLimited(&TOTPValidator{
+		Key:    []byte("12345678901234567890"),
+		Digits: EightDigits,
+	}, &budget)
+
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name  string
+		Code  int
+		Match bool
+		T     int
+		Err   error
+	}{
+		{"No Match", 7081803, false, 0x23523EC, nil},
+		{"Match", 7081804, true, 0x23523EC, nil},
+		{"Limited", 7081804, false, 0, ErrRateLimited},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			match, tMatch, err := validator.ValidateTOTPCode(testTime, test.Code)
+			if err != test.Err {
+				t.Errorf("Error did not match. Expected %v and got %v.\n", test.Err, err)
+			}
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
+
+func TestRateLimitedSecurityProfile(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Limiter     Limiter
+		Attempts    int
+		Probability float64
+	}{
+		{"Unknown Burst", new(budgetLimiter), 0, 0},
+		{"Burst", &burstLimiter{burst: 10}, 10, 1 - math.Pow(1-3/1000000.0, 10)},
+		{"Zero Burst", &burstLimiter{}, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := RateLimited(&TOTPValidator{
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+			}, test.Limiter)
+
+			profile := validator.SecurityProfile()
+			if profile.AcceptedStepsPerAttempt != 3 {
+				t.Errorf("Steps did not match. Expected 3 and got %d.\n", profile.AcceptedStepsPerAttempt)
+			}
+			if profile.AttemptsBeforeLockout != test.Attempts {
+				t.Errorf("Attempts did not match. Expected %d and got %d.\n", test.Attempts, profile.AttemptsBeforeLockout)
+			}
+			if math.Abs(profile.GuessProbabilityBeforeLockout-test.Probability) > 1e-12 {
+				t.Errorf("Probability did not match. Expected %g and got %g.\n", test.Probability, profile.GuessProbabilityBeforeLockout)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package otp
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ReplayStore records the time steps a validator has accepted codes for so each step is
+// only accepted once. Unlike LastT it allows steps within the window to be accepted out of
+// order. Implementations must be safe for concurrent use.
+type ReplayStore interface {
+	Seen(t int) bool
+	Mark(t int)
+}
+
+// ReplayCache is an in-memory ReplayStore remembering a bounded number of steps, evicting
+// the least recently marked. It should hold at least as many steps as the validator's
+// window, otherwise a step still in the window may be forgotten and accepted again.
+type ReplayCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // most recently marked first
+	steps map[int]*list.Element
+}
+
+// NewReplayCache returns a ReplayCache remembering up to size steps.
+func NewReplayCache(size int) *ReplayCache {
+	if size < 1 {
+		size = 1
+	}
+	return &ReplayCache{
+		size:  size,
+		order: list.New(),
+		steps: make(map[int]*list.Element, size),
+	}
+}
+
+// Seen returns whether t has been marked and not yet evicted.
+func (c *ReplayCache) Seen(t int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.steps[t]
+	return ok
+}
+
+// Mark records t, evicting the least recently marked step if the cache is full.
+func (c *ReplayCache) Mark(t int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.steps[t]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.steps[t] = c.order.PushFront(t)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.steps, oldest.Value.(int))
+	}
+}
+
+// used returns whether codes for step t must be rejected because it is at or before LastT
+// or has been seen by the ReplayStore.
+func (tc *TOTPValidator) used(t int) bool {
+	return t <= tc.LastT || (tc.ReplayStore != nil && tc.ReplayStore.Seen(t))
+}
+
+// recordUse consumes step t so it can't be accepted again and records a success at now.
+// The step is marked in the ReplayStore or, without one, LastT is advanced to it so a
+// ReplayStore can still accept earlier steps out of order. Callers must hold tc.mu.
+func (tc *TOTPValidator) recordUse(now time.Time, t int) {
+	if tc.ReplayStore == nil && t > tc.LastT {
+		tc.LastT = t
+	}
+	tc.mark(t)
+	tc.recordSuccessLocked(now)
+}
+
+func (tc *TOTPValidator) mark(t int) {
+	if tc.ReplayStore != nil {
+		tc.ReplayStore.Mark(t)
+	}
+}
@@ -0,0 +1,174 @@
+package otp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplayCache(t *testing.T) {
+	cache := NewReplayCache(3)
+	for _, step := range []int{1, 2, 3} {
+		cache.Mark(step)
+	}
+	// refresh 1 so 2 is the least recently marked
+	cache.Mark(1)
+	cache.Mark(4)
+
+	tests := []struct {
+		Name string
+		Step int
+		Seen bool
+	}{
+		{"Refreshed", 1, true},
+		{"Evicted", 2, false},
+		{"Retained", 3, true},
+		{"Latest", 4, true},
+		{"Never Marked", 5, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if seen := cache.Seen(test.Step); seen != test.Seen {
+				t.Errorf("Seen did not match. Expected %t and got %t.\n", test.Seen, seen)
+			}
+		})
+	}
+}
+
+func TestReplayStore(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	validate := map[string]func(tc *TOTPValidator, code int) bool{
+		"ValidateAny": func(tc *TOTPValidator, code int) bool {
+			ok, _, _ := tc.ValidateAny(now, code)
+			return ok
+		},
+		"ValidateCached": func(tc *TOTPValidator, code int) bool {
+			return tc.ValidateCached(now, code)
+		},
+		"ValidateStringCT": func(tc *TOTPValidator, code int) bool {
+			ok, _ := tc.ValidateStringCT(now, FormatCode(code, EightDigits, FormatOptions{Pad: true}))
+			return ok
+		},
+		"FixedScanSteps": func(tc *TOTPValidator, code int) bool {
+			tc.FixedScanSteps = 5
+			ok, _, _ := tc.ValidateAny(now, code)
+			return ok
+		},
+	}
+
+	for name, fn := range validate {
+		t.Run(name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+				ReplayStore:     NewReplayCache(3),
+			}
+
+			tests := []struct {
+				Name  string
+				Code  int
+				Match bool
+			}{
+				{"T+1", 14050471, true},
+				{"T-1 Out Of Order", 89731029, true},
+				{"T+1 Replayed", 14050471, false},
+				{"T", 7081804, true},
+				{"T Replayed", 7081804, false},
+				{"T-1 Replayed", 89731029, false},
+			}
+
+			for _, test := range tests {
+				if match := fn(validator, test.Code); match != test.Match {
+					t.Errorf("%s did not match. Expected %t and got %t.\n", test.Name, test.Match, match)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayStoreReadOnly(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	validator := &TOTPValidator{
+		Key:             []byte("12345678901234567890"),
+		Digits:          EightDigits,
+		PastTolerance:   30 * time.Second,
+		FutureTolerance: 30 * time.Second,
+		ReplayStore:     NewReplayCache(3),
+	}
+	validator.ReplayStore.Mark(0x23523EC)
+
+	if ok, _ := validator.ValidateTOTPCode(now, 7081804); ok {
+		t.Error("Seen step matched")
+	}
+	for i := 0; i < 2; i++ {
+		if ok, _ := validator.ValidateTOTPCode(now, 14050471); !ok {
+			t.Errorf("T+1 did not match on attempt %d", i+1)
+		}
+	}
+	if validator.ReplayStore.Seen(0x23523ED) {
+		t.Error("ValidateTOTPCode marked T+1")
+	}
+}
+
+func TestReplayWithoutStore(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	sigKey := []byte("signature key")
+	counterKey := []byte("counter key")
+
+	validate := map[string]func(tc *TOTPValidator) bool{
+		"ValidateAny": func(tc *TOTPValidator) bool {
+			ok, _, _ := tc.ValidateAny(now, 7081804)
+			return ok
+		},
+		"ValidateCached": func(tc *TOTPValidator) bool {
+			return tc.ValidateCached(now, 7081804)
+		},
+		"ValidateBetween": func(tc *TOTPValidator) bool {
+			ok, _ := tc.ValidateBetween(now.Add(-time.Minute), now, 7081804)
+			return ok
+		},
+		"ValidateStringCT": func(tc *TOTPValidator) bool {
+			ok, _ := tc.ValidateStringCT(now, "07081804")
+			return ok
+		},
+		"ValidatePinOTP": func(tc *TOTPValidator) bool {
+			ok, _ := tc.ValidatePinOTP(now, "1234", "123407081804")
+			return ok
+		},
+		"ValidateSignedRequest": func(tc *TOTPValidator) bool {
+			sig := SignRequest(sigKey, 7081804, now.Unix())
+			ok, _, _ := tc.ValidateSignedRequest(now, 7081804, now.Unix(), sig, sigKey)
+			return ok
+		},
+		"ValidateSignedCounter": func(tc *TOTPValidator) bool {
+			ok, _ := tc.ValidateSignedCounter(0x23523EC, 7081804, SignCounter(counterKey, 0x23523EC))
+			return ok
+		},
+	}
+
+	for name, fn := range validate {
+		t.Run(name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+				CounterKey:      counterKey,
+				Clock:           NewFakeClock(now),
+			}
+
+			if !fn(validator) {
+				t.Fatal("Code did not match")
+			}
+			if validator.LastT != 0x23523EC {
+				t.Errorf("LastT did not match. Expected %d and got %d.\n", 0x23523EC, validator.LastT)
+			}
+			if fn(validator) {
+				t.Error("Replayed code matched")
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package otp
+
+import (
+	"time"
+)
+
+// keys returns the keys codes are accepted for at now. After a secret is rotated the
+// PreviousKey is accepted alongside Key until RotationGrace has passed since RotatedAt,
+// keeping the old secret valid for no longer than needed.
+func (tc *TOTPValidator) keys(now time.Time) [][]byte {
+	if len(tc.PreviousKey) == 0 || tc.RotatedAt.IsZero() {
+		return [][]byte{tc.Key}
+	}
+
+	since := now.Sub(tc.RotatedAt)
+	if since < 0 || since >= tc.RotationGrace {
+		return [][]byte{tc.Key}
+	}
+
+	return [][]byte{tc.Key, tc.PreviousKey}
+}
@@ -0,0 +1,156 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestRotationGrace(t *testing.T) {
+	key := []byte("abcdefghijklmnopqrst")
+	previousKey := []byte("12345678901234567890")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name      string
+		Code      int
+		RotatedAt time.Time
+		Match     bool
+		T         int
+	}{
+		{"New Key", TOTPCode(sha1.New, key, EightDigits, 30, testTime), testTime.Add(-time.Minute), true, 0x23523EC},
+		{"New Key After Grace", TOTPCode(sha1.New, key, EightDigits, 30, testTime), testTime.Add(-time.Hour), true, 0x23523EC},
+		{"Previous Key", 7081804, testTime.Add(-time.Minute), true, 0x23523EC},
+		{"Previous Key T-1", 89731029, testTime.Add(-time.Minute), true, 0x23523EB},
+		{"Previous Key Grace Ending", 7081804, testTime.Add(-5*time.Minute + time.Nanosecond), true, 0x23523EC},
+		{"Previous Key Grace Ended", 7081804, testTime.Add(-5 * time.Minute), false, 0x23523EC},
+		{"Previous Key Before Rotation", 7081804, testTime.Add(time.Minute), false, 0x23523EC},
+		{"Previous Key Not Rotated", 7081804, time.Time{}, false, 0x23523EC},
+		{"No Match", 7081803, testTime.Add(-time.Minute), false, 0x23523EC},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:           key,
+				Digits:        EightDigits,
+				PastTolerance: 30 * time.Second,
+				PreviousKey:   previousKey,
+				RotatedAt:     test.RotatedAt,
+				RotationGrace: 5 * time.Minute,
+			}
+
+			match, tMatch := validator.ValidateTOTPCode(testTime, test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+
+			validator.FixedScanSteps = 3
+			if fixed, _ := validator.ValidateTOTPCode(testTime, test.Code); fixed != test.Match {
+				t.Errorf("Fixed scan match did not match. Expected %t and got %t.\n", test.Match, fixed)
+			}
+
+			// ValidateCached consumes the matched step so it is checked last
+			if cached := validator.ValidateCached(testTime, test.Code); cached != test.Match {
+				t.Errorf("Cached match did not match. Expected %t and got %t.\n", test.Match, cached)
+			}
+		})
+	}
+}
+
+func TestRotationGraceValidators(t *testing.T) {
+	key := []byte("abcdefghijklmnopqrst")
+	sigKey := []byte("signature key")
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	// each validates 7081804, the code for the previous key at testTime
+	tests := []struct {
+		Name     string
+		Validate func(tc *TOTPValidator) bool
+	}{
+		{"ValidateStringCT", func(tc *TOTPValidator) bool {
+			ok, _ := tc.ValidateStringCT(testTime, "07081804")
+			return ok
+		}},
+		{"ValidatePinOTP", func(tc *TOTPValidator) bool {
+			ok, _ := tc.ValidatePinOTP(testTime, "1234", "123407081804")
+			return ok
+		}},
+		{"ValidateDualPeriod", func(tc *TOTPValidator) bool {
+			ok, _, _ := tc.ValidateDualPeriod(testTime, 7081804, []int{60, 30})
+			return ok
+		}},
+		{"ValidateSignedCounter", func(tc *TOTPValidator) bool {
+			ok, err := tc.ValidateSignedCounter(0x23523EC, 7081804, SignCounter(tc.CounterKey, 0x23523EC))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			return ok
+		}},
+		{"ValidateSignedRequest", func(tc *TOTPValidator) bool {
+			ok, _, err := tc.ValidateSignedRequest(testTime, 7081804, testTime.Unix(), SignRequest(sigKey, 7081804, testTime.Unix()), sigKey)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			return ok
+		}},
+		{"ValidateBetween", func(tc *TOTPValidator) bool {
+			ok, _ := tc.ValidateBetween(testTime, testTime.Add(time.Second), 7081804)
+			return ok
+		}},
+		{"WindowBitmap", func(tc *TOTPValidator) bool {
+			return tc.WindowBitmap(testTime, 7081804)[0]
+		}},
+		{"MatchingSteps", func(tc *TOTPValidator) bool {
+			return len(tc.MatchingSteps(testTime, 7081804)) == 1
+		}},
+	}
+
+	for _, test := range tests {
+		for _, inGrace := range []bool{true, false} {
+			name := test.Name + " After Grace"
+			rotatedAt := testTime.Add(-time.Hour)
+			if inGrace {
+				name = test.Name + " During Grace"
+				rotatedAt = testTime.Add(-time.Minute)
+			}
+
+			t.Run(name, func(t *testing.T) {
+				validator := &TOTPValidator{
+					Key:           key,
+					Digits:        EightDigits,
+					CounterKey:    []byte("counter key"),
+					PreviousKey:   []byte("12345678901234567890"),
+					RotatedAt:     rotatedAt,
+					RotationGrace: 5 * time.Minute,
+					Clock:         NewFakeClock(testTime),
+				}
+
+				if ok := test.Validate(validator); ok != inGrace {
+					t.Errorf("Match did not match. Expected %t and got %t.\n", inGrace, ok)
+				}
+			})
+		}
+	}
+}
+
+func TestRotationGraceCacheExpiry(t *testing.T) {
+	testTime := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	validator := &TOTPValidator{
+		Key:           []byte("abcdefghijklmnopqrst"),
+		Digits:        EightDigits,
+		PreviousKey:   []byte("12345678901234567890"),
+		RotatedAt:     testTime.Add(-5 * time.Minute),
+		RotationGrace: 5*time.Minute + 500*time.Millisecond,
+	}
+
+	if !validator.ValidateCached(testTime, 7081804) {
+		t.Error("Previous key code did not match during grace")
+	}
+	if validator.ValidateCached(testTime.Add(500*time.Millisecond), 7081804) {
+		t.Error("Previous key code matched after grace within the same step")
+	}
+}
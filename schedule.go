@@ -0,0 +1,59 @@
+package otp
+
+import (
+	"context"
+	"time"
+)
+
+// rotationClock provides the time and timers used by ScheduleRotations.
+type rotationClock interface {
+	Clock
+	NewTimer(d time.Duration) (<-chan time.Time, func() bool)
+}
+
+type systemRotationClock struct {
+	SystemClock
+}
+
+func (systemRotationClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	timer := time.NewTimer(d)
+	return timer.C, timer.Stop
+}
+
+// ScheduleRotations calls fn with the new time step each time the TOTP code rotates, at
+// each step boundary. The delay to the next boundary is recomputed from the current time
+// on every iteration so timer inaccuracy does not accumulate, and fn is never called
+// before the boundary has been reached. fn is called synchronously; if boundaries are
+// missed, for example because fn ran longer than a step, fn is called once with the
+// latest step rather than once per missed step. ScheduleRotations blocks until ctx is done.
+func ScheduleRotations(ctx context.Context, stepSizeSeconds int, fn func(step int64)) {
+	scheduleRotations(ctx, systemRotationClock{}, stepSizeSeconds, fn)
+}
+
+func scheduleRotations(ctx context.Context, clock rotationClock, stepSizeSeconds int, fn func(step int64)) {
+	if stepSizeSeconds == 0 {
+		stepSizeSeconds = DefaultStepSizeSeconds
+	}
+
+	last := int64(timeSteps(stepSizeSeconds, clock.Now()))
+	for {
+		now := clock.Now()
+		c, stop := clock.NewTimer(NextStepTime(stepSizeSeconds, now).Sub(now))
+
+		select {
+		case <-ctx.Done():
+			stop()
+			return
+		case <-c:
+		}
+
+		step := int64(timeSteps(stepSizeSeconds, clock.Now()))
+		if step <= last {
+			// the timer fired early, wait for the remainder
+			continue
+		}
+
+		last = step
+		fn(step)
+	}
+}
@@ -0,0 +1,126 @@
+package otp
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeTimer struct {
+	d time.Duration
+	c chan time.Time
+}
+
+type fakeRotationClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers chan fakeTimer
+}
+
+func (c *fakeRotationClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeRotationClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	timer := fakeTimer{d: d, c: make(chan time.Time, 1)}
+	c.timers <- timer
+	return timer.c, func() bool { return true }
+}
+
+func (c *fakeRotationClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
+
+func TestNextStepTime(t *testing.T) {
+	tests := []struct {
+		Name     string
+		StepSize int
+		Time     time.Time
+		Next     time.Time
+	}{
+		{"Default", 0, time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), time.Date(2005, 3, 18, 1, 58, 30, 0, time.UTC)},
+		{"Step Start", 30, time.Date(2005, 3, 18, 1, 58, 30, 0, time.UTC), time.Date(2005, 3, 18, 1, 59, 0, 0, time.UTC)},
+		{"Sub-Second", 30, time.Date(2005, 3, 18, 1, 58, 59, 999, time.UTC), time.Date(2005, 3, 18, 1, 59, 0, 0, time.UTC)},
+		{"60 Seconds", 60, time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), time.Date(2005, 3, 18, 1, 59, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			next := NextStepTime(test.StepSize, test.Time)
+			if !next.Equal(test.Next) {
+				t.Errorf("Next did not match. Expected %s and got %s.\n", test.Next, next)
+			}
+		})
+	}
+}
+
+func TestScheduleRotations(t *testing.T) {
+	clock := &fakeRotationClock{
+		now:    time.Date(2005, 3, 18, 1, 58, 29, int(750*time.Millisecond), time.UTC),
+		timers: make(chan fakeTimer),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	steps := make(chan int64, 10)
+	done := make(chan struct{})
+	go func() {
+		scheduleRotations(ctx, clock, 30, func(step int64) { steps <- step })
+		close(done)
+	}()
+
+	expectTimer := func(d time.Duration) fakeTimer {
+		t.Helper()
+		timer := <-clock.timers
+		if timer.d != d {
+			t.Errorf("Timer did not match. Expected %s and got %s.\n", d, timer.d)
+		}
+		return timer
+	}
+
+	expectStep := func(step int64) {
+		t.Helper()
+		select {
+		case s := <-steps:
+			if s != step {
+				t.Errorf("Step did not match. Expected %d and got %d.\n", step, s)
+			}
+		case timer := <-clock.timers:
+			t.Fatalf("Expected step %d but a %s timer was started", step, timer.d)
+		}
+	}
+
+	// first boundary is 250ms away
+	timer := expectTimer(250 * time.Millisecond)
+	// the timer fires 5ms late
+	timer.c <- clock.Advance(255 * time.Millisecond)
+	expectStep(0x23523ED)
+
+	// the next timer corrects for the late firing
+	timer = expectTimer(30*time.Second - 5*time.Millisecond)
+	// the timer fires 10ms early so no rotation is reported yet
+	timer.c <- clock.Advance(30*time.Second - 15*time.Millisecond)
+	timer = expectTimer(10 * time.Millisecond)
+	timer.c <- clock.Advance(10 * time.Millisecond)
+	expectStep(0x23523EE)
+
+	// fn overran an entire step so the skipped step isn't reported late
+	timer = expectTimer(30 * time.Second)
+	timer.c <- clock.Advance(65 * time.Second)
+	expectStep(0x23523F0)
+
+	expectTimer(25 * time.Second)
+	cancel()
+	<-done
+
+	select {
+	case s := <-steps:
+		t.Errorf("Unexpected step %d after cancel", s)
+	default:
+	}
+}
@@ -0,0 +1,178 @@
+package otp
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Secret sizes accepted by GenerateSecret.
+const (
+	DefaultSecretBytes = 20 // the output size of SHA1 as recommended by RFC 4226
+	MinSecretBytes     = 16 // the 128 bit minimum of RFC 4226
+)
+
+// secretEncoding is the unpadded base32 encoding of secrets in otpauth URIs.
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random shared secret of the given number of bytes read
+// from crypto/rand. A zero size generates DefaultSecretBytes. An error is returned for
+// sizes below MinSecretBytes.
+func GenerateSecret(bytes int) ([]byte, error) {
+	if bytes == 0 {
+		bytes = DefaultSecretBytes
+	}
+	if bytes < MinSecretBytes {
+		return nil, fmt.Errorf("otp: secret of %d bytes is too short, must be at least %d", bytes, MinSecretBytes)
+	}
+
+	secret := make([]byte, bytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("otp: generating secret: %w", err)
+	}
+	return secret, nil
+}
+
+// GenerateSecretBase32 is GenerateSecret returning the secret as unpadded uppercase
+// base32, as used in otpauth URIs.
+func GenerateSecretBase32(bytes int) (string, error) {
+	secret, err := GenerateSecret(bytes)
+	if err != nil {
+		return "", err
+	}
+	return secretEncoding.EncodeToString(secret), nil
+}
+
+// DecodeSecret decodes a base32 secret as entered by a user. Case is ignored, spaces and
+// dashes used to group characters are removed and trailing = padding is optional.
+// The error for an invalid secret names the offending character.
+func DecodeSecret(s string) ([]byte, error) {
+	s = strings.ToUpper(s)
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, s)
+	s = strings.TrimRight(s, "=")
+
+	if s == "" {
+		return nil, errors.New("otp: secret is empty")
+	}
+	for i, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '2' || r > '7') {
+			return nil, fmt.Errorf("otp: invalid character %q at position %d in secret", r, i)
+		}
+	}
+
+	// unpadded decoding accepts lengths that don't end on a whole byte
+	switch len(s) % 8 {
+	case 1, 3, 6:
+		return nil, fmt.Errorf("otp: invalid secret length %d", len(s))
+	}
+
+	return secretEncoding.DecodeString(s)
+}
+
+// DecodeBase32 decodes a base32 secret. It is DecodeSecret, named alongside DecodeBase64
+// and DecodeHex for when the encoding of a secret is known.
+func DecodeBase32(s string) ([]byte, error) {
+	return DecodeSecret(s)
+}
+
+// DecodeBase64 decodes a base64 secret as issued by Steam and some vendors. Whitespace is
+// removed and padding is optional. Both the standard and URL safe alphabets are accepted.
+func DecodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(removeSpace(s), "=")
+	if s == "" {
+		return nil, errors.New("otp: secret is empty")
+	}
+
+	encoding := base64.RawStdEncoding
+	if strings.ContainsAny(s, "-_") {
+		encoding = base64.RawURLEncoding
+	}
+
+	secret, err := encoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("otp: invalid base64 secret: %w", err)
+	}
+	return secret, nil
+}
+
+// DecodeHex decodes a hexadecimal secret. Whitespace is removed and a 0x prefix is
+// optional.
+func DecodeHex(s string) ([]byte, error) {
+	s = removeSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	if s == "" {
+		return nil, errors.New("otp: secret is empty")
+	}
+
+	secret, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("otp: invalid hex secret: %w", err)
+	}
+	return secret, nil
+}
+
+// Plausible secret sizes for ParseSecret. Many services issue 80 bit secrets of 16 base32
+// characters, below the MinSecretBytes recommended for new secrets.
+const (
+	minParsedSecretBytes = 10
+	maxParsedSecretBytes = 128
+)
+
+// ParseSecret decodes a secret whose encoding is unknown, trying base32, then hex, then
+// base64 and returning the first that decodes to a plausible key of 10 to 128 bytes.
+// Hex is tried before base64 because almost every hex string is also valid base64, and
+// is only tried for strings made entirely of hex digits. A string valid in more than one
+// encoding, such as base64 without digits, decodes as the first.
+// An error is returned if no encoding fits.
+func ParseSecret(s string) ([]byte, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, errors.New("otp: secret is empty")
+	}
+
+	decoders := []func(string) ([]byte, error){DecodeBase32, decodeHexDigits, DecodeBase64}
+	for _, decode := range decoders {
+		secret, err := decode(s)
+		if err == nil && len(secret) >= minParsedSecretBytes && len(secret) <= maxParsedSecretBytes {
+			return secret, nil
+		}
+	}
+
+	return nil, errors.New("otp: secret is not a plausible base32, hex or base64 key")
+}
+
+// decodeHexDigits is DecodeHex for strings of only hex digits and whitespace, which
+// rules out base64 that happens to be valid hex.
+func decodeHexDigits(s string) ([]byte, error) {
+	digits := removeSpace(s)
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		digits = digits[2:]
+	}
+	for _, r := range digits {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return nil, fmt.Errorf("otp: invalid character %q in hex secret", r)
+		}
+	}
+	return DecodeHex(digits)
+}
+
+// removeSpace returns s with all whitespace removed.
+func removeSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
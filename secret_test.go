@@ -0,0 +1,214 @@
+package otp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGenerateSecret(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Bytes  int
+		Length int
+		Err    string
+	}{
+		{"Default", 0, DefaultSecretBytes, ""},
+		{"Minimum", MinSecretBytes, MinSecretBytes, ""},
+		{"SHA512", 64, 64, ""},
+		{"Too Short", 15, 0, "otp: secret of 15 bytes is too short, must be at least 16"},
+		{"Negative", -1, 0, "otp: secret of -1 bytes is too short, must be at least 16"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			secret, err := GenerateSecret(test.Bytes)
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(secret) != test.Length {
+				t.Errorf("Length did not match. Expected %d and got %d.\n", test.Length, len(secret))
+			}
+
+			other, _ := GenerateSecret(test.Bytes)
+			if bytes.Equal(secret, other) {
+				t.Errorf("Secrets were not random: %x", secret)
+			}
+		})
+	}
+}
+
+func TestGenerateSecretBase32(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Bytes  int
+		Length int
+	}{
+		{"Default", 0, 32},
+		{"Minimum", MinSecretBytes, 26},
+		{"SHA256", 32, 52},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			secret, err := GenerateSecretBase32(test.Bytes)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(secret) != test.Length {
+				t.Errorf("Length did not match. Expected %d and got %d.\n", test.Length, len(secret))
+			}
+
+			key, err := secretEncoding.DecodeString(secret)
+			if err != nil {
+				t.Errorf("Secret %s did not decode: %v", secret, err)
+			}
+			if test.Bytes != 0 && len(key) != test.Bytes {
+				t.Errorf("Key length did not match. Expected %d and got %d.\n", test.Bytes, len(key))
+			}
+		})
+	}
+
+	if _, err := GenerateSecretBase32(8); err == nil {
+		t.Error("Expected an error for a short secret")
+	}
+}
+
+func TestDecodeSecret(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name   string
+		Secret string
+		Key    []byte
+		Err    string
+	}{
+		{"Unpadded", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", key, ""},
+		{"Lowercase", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", key, ""},
+		{"Grouped", "gezd gnbv gy3t qojq-gezd gnbv gy3t qojq", key, ""},
+		{"Padded", "MZXW6===", []byte("foo"), ""},
+		{"Unpadded Partial", "MZXW6", []byte("foo"), ""},
+		{"Empty", " - ", nil, "otp: secret is empty"},
+		{"Invalid Digit", "GEZDGNBV1Y3TQOJQ", nil, `otp: invalid character '1' at position 8 in secret`},
+		{"Invalid Symbol", "GEZD_GNBV", nil, `otp: invalid character '_' at position 4 in secret`},
+		{"Inner Padding", "MZ=XW6", nil, `otp: invalid character '=' at position 2 in secret`},
+		{"Invalid Length", "MZXW6Y", nil, "otp: invalid secret length 6"},
+		{"Single Character", "M", nil, "otp: invalid secret length 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			secret, err := DecodeSecret(test.Secret)
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !bytes.Equal(secret, test.Key) {
+				t.Errorf("Key did not match. Expected %x and got %x.\n", test.Key, secret)
+			}
+		})
+	}
+}
+
+func TestDecodeBase64(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Secret string
+		Key    []byte
+		Err    bool
+	}{
+		{"Padded", "MTIzNDU2Nzg5MDEyMzQ1Njc4OTA=", []byte("12345678901234567890"), false},
+		{"Unpadded", "MTIzNDU2Nzg5MDEyMzQ1Njc4OTA", []byte("12345678901234567890"), false},
+		{"Whitespace", " MTIzNDU2 Nzg5MDEy\nMzQ1Njc4OTA= ", []byte("12345678901234567890"), false},
+		{"Standard Alphabet", "+/+/", []byte{0xfb, 0xff, 0xbf}, false},
+		{"URL Alphabet", "-_-_", []byte{0xfb, 0xff, 0xbf}, false},
+		{"Empty", " = ", nil, true},
+		{"Invalid", "MTIz*DU2", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			secret, err := DecodeBase64(test.Secret)
+			if (err != nil) != test.Err {
+				t.Errorf("Error did not match. Expected %t and got %v.\n", test.Err, err)
+			}
+			if !bytes.Equal(secret, test.Key) {
+				t.Errorf("Key did not match. Expected %x and got %x.\n", test.Key, secret)
+			}
+		})
+	}
+}
+
+func TestDecodeHex(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Secret string
+		Key    []byte
+		Err    bool
+	}{
+		{"Lowercase", "3132333435", []byte("12345"), false},
+		{"Uppercase", "DEADBEEF", []byte{0xde, 0xad, 0xbe, 0xef}, false},
+		{"Prefixed", "0xdeadbeef", []byte{0xde, 0xad, 0xbe, 0xef}, false},
+		{"Grouped", "dead beef", []byte{0xde, 0xad, 0xbe, 0xef}, false},
+		{"Empty", "0x", nil, true},
+		{"Odd Length", "deadbee", nil, true},
+		{"Invalid", "deadbeeg", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			secret, err := DecodeHex(test.Secret)
+			if (err != nil) != test.Err {
+				t.Errorf("Error did not match. Expected %t and got %v.\n", test.Err, err)
+			}
+			if !bytes.Equal(secret, test.Key) {
+				t.Errorf("Key did not match. Expected %x and got %x.\n", test.Key, secret)
+			}
+		})
+	}
+}
+
+func TestParseSecret(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name   string
+		Secret string
+		Key    []byte
+		Err    bool
+	}{
+		{"Base32", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", key, false},
+		{"Base32 Grouped", " gezd gnbv gy3t qojq gezd gnbv gy3t qojq ", key, false},
+		{"Base32 80 Bit", "GEZDGNBVGY3TQOJQ", []byte("1234567890"), false},
+		{"Hex", "3132333435363738393031323334353637383930", key, false},
+		{"Hex Prefixed", "0x3132333435363738393031323334353637383930", key, false},
+		{"Base64", "MTIzNDU2Nzg5MDEyMzQ1Njc4OTA=", key, false},
+		{"Base64 URL", "-_-_-_-_-_-_-_-_", bytes.Repeat([]byte{0xfb, 0xff, 0xbf}, 4), false},
+		{"Too Short", "MZXW6===", nil, true},
+		{"Too Short Hex", "deadbeef", nil, true},
+		{"Empty", "  ", nil, true},
+		{"Not A Secret", "not a secret!", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			secret, err := ParseSecret(test.Secret)
+			if (err != nil) != test.Err {
+				t.Errorf("Error did not match. Expected %t and got %v.\n", test.Err, err)
+			}
+			if !bytes.Equal(secret, test.Key) {
+				t.Errorf("Key did not match. Expected %x and got %x.\n", test.Key, secret)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package otp
+
+import (
+	"math"
+)
+
+// SecurityProfile summarises how resistant a validator's configuration is to guessing.
+type SecurityProfile struct {
+	// EffectiveCodeSpace is the number of distinct codes.
+	EffectiveCodeSpace int
+	// AcceptedStepsPerAttempt is the most time steps a single attempt is checked against,
+	// counting each step once per key while a previous key is accepted after rotation.
+	AcceptedStepsPerAttempt int
+	// GuessProbabilityPerAttempt is the chance a random guess is accepted.
+	GuessProbabilityPerAttempt float64
+	// BitsPerAttempt is the entropy a random guess has to overcome, log2 of 1/GuessProbabilityPerAttempt.
+	BitsPerAttempt float64
+	// AttemptsBeforeLockout is the most attempts allowed before a limiter rejects further
+	// guesses, or 0 if no limiter with a known burst is configured.
+	AttemptsBeforeLockout int
+	// GuessProbabilityBeforeLockout is the chance one of AttemptsBeforeLockout random
+	// guesses is accepted, or 0 if AttemptsBeforeLockout is 0.
+	GuessProbabilityBeforeLockout float64
+}
+
+// SecurityProfile returns the brute force resistance of the validator's configuration.
+// It describes the worst case: LastT is ignored and the widest window, including any
+// tolerance granted to newly enrolled tokens, is assumed, as is a rotation grace period
+// in which PreviousKey is also accepted. A TOTPValidator has no lockout of its own so
+// AttemptsBeforeLockout is 0; see RateLimitedValidator.SecurityProfile.
+func (tc *TOTPValidator) SecurityProfile() SecurityProfile {
+	space := tc.digits().GuaranteedUniqueValues()
+	accepted := tc.maxAcceptedSteps()
+
+	probability := float64(accepted) / float64(space)
+	if probability > 1 {
+		probability = 1
+	}
+
+	return SecurityProfile{
+		EffectiveCodeSpace:         space,
+		AcceptedStepsPerAttempt:    accepted,
+		GuessProbabilityPerAttempt: probability,
+		BitsPerAttempt:             -math.Log2(probability),
+	}
+}
+
+// withLockout returns the profile with the attempts allowed before lockout filled in.
+func (p SecurityProfile) withLockout(attempts int) SecurityProfile {
+	if attempts <= 0 {
+		return p
+	}
+
+	p.AttemptsBeforeLockout = attempts
+	p.GuessProbabilityBeforeLockout = 1 - math.Pow(1-p.GuessProbabilityPerAttempt, float64(attempts))
+	return p
+}
+
+// maxAcceptedSteps returns the most time steps the validator accepts at any instant,
+// counted once for each key.
+func (tc *TOTPValidator) maxAcceptedSteps() int {
+	keys := 1
+	if len(tc.PreviousKey) > 0 && !tc.RotatedAt.IsZero() && tc.RotationGrace > 0 {
+		keys = 2
+	}
+	return tc.maxWindowSize() * keys
+}
+
+// maxWindowSize returns the most time steps in the validator's window at any instant.
+func (tc *TOTPValidator) maxWindowSize() int {
+	if len(tc.AcceptOffsets) > 0 {
+		offsets := make(map[int]bool, len(tc.AcceptOffsets))
+		for _, offset := range tc.AcceptOffsets {
+			offsets[offset] = true
+		}
+		return len(offsets)
+	}
+
+	pastTolerance, futureTolerance := tc.pastTolerance(), tc.futureTolerance()
+	if tc.EnrollmentAge > 0 && tc.EnrollmentTolerance > pastTolerance {
+		pastTolerance = tc.EnrollmentTolerance
+	}
+	if tc.EnrollmentAge > 0 && tc.EnrollmentTolerance > futureTolerance {
+		futureTolerance = tc.EnrollmentTolerance
+	}
+
+	// a window that isn't a whole number of steps can straddle one extra step
+	stepSize := tc.stepSize()
+	width := pastTolerance + futureTolerance
+	if width < 0 {
+		// a negative tolerance can empty the window
+		return 0
+	}
+	steps := int(width/stepSize) + 1
+	if width%stepSize != 0 {
+		steps++
+	}
+	if max := tc.maxWindowSteps(); max > 0 && steps > max {
+		steps = max
+	}
+	return steps
+}
@@ -0,0 +1,106 @@
+package otp
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSecurityProfile(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Digits          Digits
+		PastTolerance   time.Duration
+		FutureTolerance time.Duration
+		Offsets         []int
+		Enrollment      time.Duration
+		Space           int
+		Steps           int
+		Bits            float64
+	}{
+		{"Default", 0, 0, 0, nil, 0, 1000000, 1, 19.9316},
+		{"Eight Digits", EightDigits, 0, 0, nil, 0, 100000000, 1, 26.5754},
+		{"1 Window", SixDigits, 30 * time.Second, 30 * time.Second, nil, 0, 1000000, 3, 18.3466},
+		{"Past Window", SixDigits, 30 * time.Second, 0, nil, 0, 1000000, 2, 18.9316},
+		{".5 Window", SixDigits, 15 * time.Second, 0, nil, 0, 1000000, 2, 18.9316},
+		{"1.5 Window", SixDigits, 45 * time.Second, 0, nil, 0, 1000000, 3, 18.3466},
+		{"Offsets", SixDigits, time.Hour, time.Hour, []int{-1, 0, 0, 1, 3}, 0, 1000000, 4, 17.9316},
+		{"Enrollment", SixDigits, 30 * time.Second, 0, nil, 60 * time.Second, 1000000, 5, 17.6097},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Digits:              test.Digits,
+				PastTolerance:       test.PastTolerance,
+				FutureTolerance:     test.FutureTolerance,
+				AcceptOffsets:       test.Offsets,
+				EnrollmentAge:       24 * time.Hour,
+				EnrollmentTolerance: test.Enrollment,
+			}
+
+			profile := validator.SecurityProfile()
+			if profile.EffectiveCodeSpace != test.Space {
+				t.Errorf("Code space did not match. Expected %d and got %d.\n", test.Space, profile.EffectiveCodeSpace)
+			}
+			if profile.AcceptedStepsPerAttempt != test.Steps {
+				t.Errorf("Steps did not match. Expected %d and got %d.\n", test.Steps, profile.AcceptedStepsPerAttempt)
+			}
+			probability := float64(test.Steps) / float64(test.Space)
+			if math.Abs(profile.GuessProbabilityPerAttempt-probability) > 1e-12 {
+				t.Errorf("Probability did not match. Expected %g and got %g.\n", probability, profile.GuessProbabilityPerAttempt)
+			}
+			if math.Abs(profile.BitsPerAttempt-test.Bits) > 0.0001 {
+				t.Errorf("Bits did not match. Expected %f and got %f.\n", test.Bits, profile.BitsPerAttempt)
+			}
+		})
+	}
+}
+
+func TestSecurityProfileRotationGrace(t *testing.T) {
+	validator := &TOTPValidator{
+		PastTolerance:   30 * time.Second,
+		FutureTolerance: 30 * time.Second,
+		PreviousKey:     []byte("12345678901234567890"),
+		RotatedAt:       time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC),
+		RotationGrace:   time.Hour,
+	}
+
+	// codes for both keys are accepted for each of the 3 steps
+	profile := validator.SecurityProfile()
+	if profile.AcceptedStepsPerAttempt != 6 {
+		t.Errorf("Steps did not match. Expected 6 and got %d.\n", profile.AcceptedStepsPerAttempt)
+	}
+	if probability := 6 / 1000000.0; math.Abs(profile.GuessProbabilityPerAttempt-probability) > 1e-12 {
+		t.Errorf("Probability did not match. Expected %g and got %g.\n", probability, profile.GuessProbabilityPerAttempt)
+	}
+
+	validator.RotationGrace = 0
+	if steps := validator.SecurityProfile().AcceptedStepsPerAttempt; steps != 3 {
+		t.Errorf("Steps without grace did not match. Expected 3 and got %d.\n", steps)
+	}
+}
+
+func TestMaxAcceptedStepsBoundsWindow(t *testing.T) {
+	tolerances := []time.Duration{0, time.Second, 15 * time.Second, 30 * time.Second, 45 * time.Second, 60 * time.Second}
+	start := time.Date(2005, 3, 18, 1, 58, 0, 0, time.UTC)
+
+	for _, past := range tolerances {
+		for _, future := range tolerances {
+			validator := &TOTPValidator{PastTolerance: past, FutureTolerance: future}
+			max := validator.maxAcceptedSteps()
+
+			reached := false
+			for offset := time.Duration(0); offset < 30*time.Second; offset += 500 * time.Millisecond {
+				steps := len(validator.steps(start.Add(offset)))
+				if steps > max {
+					t.Errorf("Window of %d steps exceeded max %d for past %s future %s", steps, max, past, future)
+				}
+				reached = reached || steps == max
+			}
+			if !reached {
+				t.Errorf("Max %d steps never reached for past %s future %s", max, past, future)
+			}
+		}
+	}
+}
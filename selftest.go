@@ -0,0 +1,145 @@
+package otp
+
+import (
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+)
+
+// rfc4226Vectors are the HOTP values from RFC 4226 Appendix D.
+var rfc4226Vectors = []int{755224, 287082, 359152, 969429, 338314, 254676, 287922, 162583, 399871, 520489}
+
+// rfc6238Vectors are the TOTP values from RFC 6238 Appendix B.
+var rfc6238Vectors = []struct {
+	name string
+	key  []byte
+	unix int64
+	code int
+}{
+	{"SHA1", []byte("12345678901234567890"), 59, 94287082},
+	{"SHA256", []byte("12345678901234567890123456789012"), 59, 46119246},
+	{"SHA512", []byte("1234567890123456789012345678901234567890123456789012345678901234"), 59, 90693936},
+	{"SHA1", []byte("12345678901234567890"), 1111111109, 7081804},
+	{"SHA256", []byte("12345678901234567890123456789012"), 1111111109, 68084774},
+	{"SHA512", []byte("1234567890123456789012345678901234567890123456789012345678901234"), 1111111109, 25091201},
+	{"SHA1", []byte("12345678901234567890"), 1111111111, 14050471},
+	{"SHA256", []byte("12345678901234567890123456789012"), 1111111111, 67062674},
+	{"SHA512", []byte("1234567890123456789012345678901234567890123456789012345678901234"), 1111111111, 99943326},
+	{"SHA1", []byte("12345678901234567890"), 1234567890, 89005924},
+	{"SHA256", []byte("12345678901234567890123456789012"), 1234567890, 91819424},
+	{"SHA512", []byte("1234567890123456789012345678901234567890123456789012345678901234"), 1234567890, 93441116},
+	{"SHA1", []byte("12345678901234567890"), 2000000000, 69279037},
+	{"SHA256", []byte("12345678901234567890123456789012"), 2000000000, 90698825},
+	{"SHA512", []byte("1234567890123456789012345678901234567890123456789012345678901234"), 2000000000, 38618901},
+	{"SHA1", []byte("12345678901234567890"), 20000000000, 65353130},
+	{"SHA256", []byte("12345678901234567890123456789012"), 20000000000, 77737706},
+	{"SHA512", []byte("1234567890123456789012345678901234567890123456789012345678901234"), 20000000000, 47863826},
+}
+
+// SelfTest checks code generation and validation against the RFC 4226 and RFC 6238
+// test vectors using the SHA1, SHA256 and SHA512 providers registered with RegisterHash.
+// Any other registered algorithm has no published vectors so it is checked to produce
+// consistent codes that its validators accept. A non-nil error indicates a broken build,
+// crypto implementation or registered hash provider.
+func SelfTest() error {
+	sha1Provider, err := HashProviderByName("SHA1")
+	if err != nil {
+		return fmt.Errorf("otp: self test failed: %v", err)
+	}
+	for i, expected := range rfc4226Vectors {
+		code := HOTPCode(sha1Provider, []byte("12345678901234567890"), SixDigits, int64(i))
+		if code != expected {
+			return fmt.Errorf("otp: RFC 4226 self test failed for counter %d: expected %d but got %d", i, expected, code)
+		}
+	}
+
+	for _, vector := range rfc6238Vectors {
+		hashProvider, err := HashProviderByName(vector.name)
+		if err != nil {
+			return fmt.Errorf("otp: RFC 6238 self test failed: %v", err)
+		}
+		t := time.Unix(vector.unix, 0)
+
+		code := TOTPCode(hashProvider, vector.key, EightDigits, DefaultStepSizeSeconds, t)
+		if code != vector.code {
+			return fmt.Errorf("otp: RFC 6238 self test failed for %s at %d: expected %d but got %d", vector.name, vector.unix, vector.code, code)
+		}
+
+		validator := &TOTPValidator{
+			Key:          vector.key,
+			HashProvider: hashProvider,
+			Digits:       EightDigits,
+		}
+		if ok, _ := validator.ValidateTOTPCode(t, vector.code); !ok {
+			return fmt.Errorf("otp: RFC 6238 self test failed to validate %s at %d", vector.name, vector.unix)
+		}
+	}
+
+	for _, algorithm := range registeredHashes() {
+		if hasRFCVectors(algorithm.name) {
+			continue
+		}
+		if err := selfTestHash(algorithm.name, algorithm.hashProvider); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasRFCVectors returns whether the RFC 6238 vectors cover the algorithm name.
+func hasRFCVectors(name string) bool {
+	for _, vector := range rfc6238Vectors {
+		if strings.EqualFold(vector.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// selfTestHash checks that a registered algorithm without test vectors generates the same
+// codes for HOTP and TOTP on every call and that its validators accept them. RegisterHash
+// has already rejected digests too short for dynamic truncation.
+func selfTestHash(name string, hashProvider func() hash.Hash) error {
+	key := []byte("12345678901234567890")
+
+	for _, vector := range rfc6238Vectors {
+		t := time.Unix(vector.unix, 0)
+		counter := t.Unix() / DefaultStepSizeSeconds
+
+		code := TOTPCode(hashProvider, key, EightDigits, DefaultStepSizeSeconds, t)
+		if hotp := HOTPCode(hashProvider, key, EightDigits, counter); hotp != code {
+			return fmt.Errorf("otp: self test failed for %s at %d: TOTP code %d doesn't match HOTP code %d", name, vector.unix, code, hotp)
+		}
+
+		validator := &TOTPValidator{
+			Key:          key,
+			HashProvider: hashProvider,
+			Digits:       EightDigits,
+		}
+		if ok, _ := validator.ValidateTOTPCode(t, code); !ok {
+			return fmt.Errorf("otp: self test failed to validate %s at %d", name, vector.unix)
+		}
+
+		hotpValidator := &HOTPValidator{
+			Key:          key,
+			HashProvider: hashProvider,
+			Digits:       EightDigits,
+			Counter:      counter,
+		}
+		if ok, _ := hotpValidator.Validate(code); !ok {
+			return fmt.Errorf("otp: self test failed to validate %s for counter %d", name, counter)
+		}
+	}
+
+	return nil
+}
+
+// MustSelfTest runs SelfTest and panics if it fails. It is intended to be called
+// during program startup so a broken build fails fast rather than rejecting codes.
+func MustSelfTest() {
+	if err := SelfTest(); err != nil {
+		panic(err)
+	}
+}
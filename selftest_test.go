@@ -0,0 +1,66 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Errorf("Self test failed: %v", err)
+	}
+}
+
+// driftingHash is a hash whose digest changes on every Sum.
+type driftingHash struct {
+	hash.Hash
+	sums *int
+}
+
+func (h driftingHash) Sum(b []byte) []byte {
+	*h.sums++
+	h.Hash.Write([]byte{byte(*h.sums)})
+	return h.Hash.Sum(b)
+}
+
+func TestSelfTestRegisteredHashes(t *testing.T) {
+	sums := 0
+
+	tests := []struct {
+		Name         string
+		HashName     string
+		HashProvider func() hash.Hash
+		OK           bool
+	}{
+		{"Alias", "SHA1-ALIAS", sha1.New, true},
+		{"Replaced Default", "sha256", sha1.New, false},
+		{"Inconsistent", "DRIFTING", func() hash.Hash { return driftingHash{sha256.New(), &sums} }, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			defer saveHashRegistry()()
+
+			if err := RegisterHash(test.HashName, test.HashProvider); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			err := SelfTest()
+			if ok := err == nil; ok != test.OK {
+				t.Errorf("Self test result did not match. Expected %t and got %t (%v).\n", test.OK, ok, err)
+			}
+		})
+	}
+}
+
+func TestMustSelfTest(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Self test panicked: %v", r)
+		}
+	}()
+
+	MustSelfTest()
+}
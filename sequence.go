@@ -0,0 +1,99 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"hash"
+)
+
+// hotpGenerator computes HOTP codes for many values under one key, keying the HMAC once
+// and resetting it between values. aad, when set, is appended to each value before the
+// HMAC as for TOTPCodeAAD.
+type hotpGenerator struct {
+	mac    hash.Hash
+	digits Digits
+	value  [8]byte
+	aad    []byte
+	sum    []byte
+}
+
+// newHOTPGenerator returns a generator for key, with the same errors as HOTPCodeErr.
+func newHOTPGenerator(hashProvider func() hash.Hash, key []byte, digits Digits) (*hotpGenerator, error) {
+	switch {
+	case hashProvider == nil:
+		return nil, ErrNilHashProvider
+	case len(key) == 0:
+		return nil, ErrEmptyKey
+	case digits == 0:
+		return nil, ErrZeroDigits
+	}
+
+	mac := hmac.New(hashProvider, key)
+	return &hotpGenerator{mac: mac, digits: digits, sum: make([]byte, 0, mac.Size())}, nil
+}
+
+// mustHOTPGenerator is newHOTPGenerator panicking on error as HOTPCode does.
+func mustHOTPGenerator(hashProvider func() hash.Hash, key []byte, digits Digits) *hotpGenerator {
+	g, err := newHOTPGenerator(hashProvider, key, digits)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// code returns the HOTP code for value.
+func (g *hotpGenerator) code(value int64) int {
+	g.mac.Reset()
+	binary.BigEndian.PutUint64(g.value[:], uint64(value))
+	g.mac.Write(g.value[:])
+	g.mac.Write(g.aad)
+	g.sum = g.mac.Sum(g.sum[:0])
+
+	return int(uint64(dynamicTruncate(g.sum)) % uint64(g.digits))
+}
+
+// HOTPCodeSequence returns the count consecutive HOTP codes for the values start,
+// start+1 and so on, as printed on backup sheets. The HMAC is keyed once for the whole
+// sequence. The slice for all count codes is allocated up front so for very large counts
+// HOTPCodes, which yields codes one at a time, avoids holding them all in memory. nil is
+// returned if count is not positive. HOTPCodeSequence panics if HOTPCodeErr would return
+// an error.
+func HOTPCodeSequence(hashProvider func() hash.Hash, key []byte, digits Digits, start int64, count int) []int {
+	if count <= 0 {
+		return nil
+	}
+
+	codes := make([]int, 0, count)
+	HOTPCodes(hashProvider, key, digits, start, count)(func(_ int64, code int) bool {
+		codes = append(codes, code)
+		return true
+	})
+
+	return codes
+}
+
+// HOTPCodes returns an iterator over the count consecutive HOTP codes starting at the value
+// start. It yields each value along with its code and stops early if yield returns false.
+// From Go 1.23 it can be used with range:
+//
+//	for value, code := range otp.HOTPCodes(sha1.New, key, otp.SixDigits, 0, 100) {
+//		...
+//	}
+//
+// The HMAC is keyed once per iteration of the sequence. The returned function panics if
+// HOTPCodeErr would return an error.
+func HOTPCodes(hashProvider func() hash.Hash, key []byte, digits Digits, start int64, count int) func(yield func(int64, int) bool) {
+	return func(yield func(int64, int) bool) {
+		if count <= 0 {
+			return
+		}
+
+		g := mustHOTPGenerator(hashProvider, key, digits)
+		for i := 0; i < count; i++ {
+			value := start + int64(i)
+			if !yield(value, g.code(value)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"reflect"
+	"testing"
+)
+
+func TestHOTPCodeSequence(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name  string
+		Start int64
+		Count int
+		Codes []int
+	}{
+		{"All Vectors", 0, 10, rfc4226Vectors[:]},
+		{"Offset Start", 7, 3, rfc4226Vectors[7:]},
+		{"Single", 4, 1, rfc4226Vectors[4:5]},
+		{"Zero Count", 0, 0, nil},
+		{"Negative Count", 0, -1, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			codes := HOTPCodeSequence(sha1.New, key, SixDigits, test.Start, test.Count)
+			if !reflect.DeepEqual(codes, test.Codes) {
+				t.Errorf("Codes did not match. Expected %v and got %v.\n", test.Codes, codes)
+			}
+		})
+	}
+}
+
+func TestHOTPCodes(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	var values []int64
+	var codes []int
+	HOTPCodes(sha1.New, key, SixDigits, 2, 10)(func(value int64, code int) bool {
+		values = append(values, value)
+		codes = append(codes, code)
+		return len(codes) < 3
+	})
+
+	expectedValues := []int64{2, 3, 4}
+	if !reflect.DeepEqual(values, expectedValues) {
+		t.Errorf("Values did not match. Expected %v and got %v.\n", expectedValues, values)
+	}
+	if !reflect.DeepEqual(codes, rfc4226Vectors[2:5]) {
+		t.Errorf("Codes did not match. Expected %v and got %v.\n", rfc4226Vectors[2:5], codes)
+	}
+}
+
+func TestHOTPCodesPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrEmptyKey {
+			t.Errorf("Panic did not match. Expected %v and got %v.\n", ErrEmptyKey, r)
+		}
+	}()
+	HOTPCodes(sha1.New, nil, SixDigits, 0, 1)(func(int64, int) bool { return true })
+}
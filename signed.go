@@ -0,0 +1,126 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// Errors returned when validating signed counters and requests.
+var (
+	ErrMissingCounterKey       = errors.New("otp: validator has no CounterKey")
+	ErrInvalidCounterMAC       = errors.New("otp: counter MAC is invalid")
+	ErrMissingSignatureKey     = errors.New("otp: no signature key provided")
+	ErrInvalidRequestSignature = errors.New("otp: request signature is invalid")
+	ErrClientTimeOutOfRange    = errors.New("otp: client time is outside the accepted range")
+)
+
+// SignCounter returns the HMAC-SHA256 of counter under macKey as expected by
+// TOTPValidator.ValidateSignedCounter.
+func SignCounter(macKey []byte, counter int64) []byte {
+	h := hmac.New(sha256.New, macKey)
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(counter))
+	h.Write(buf[:])
+
+	return h.Sum(nil)
+}
+
+// ValidateSignedCounter returns a bool indicating if code is valid for the provided counter.
+// The counter must be accompanied by a mac produced by SignCounter under the validator's
+// CounterKey. As the counter is authenticated only that exact counter is checked rather
+// than a tolerance window. Counters at or before LastT or seen by ReplayStore are
+// rejected and an accepted counter is consumed as for ValidateStringCT. As no time is
+// provided LastSuccess is set to the current time of the validator's Clock on success.
+// An error is returned if the CounterKey is missing or the mac does not verify.
+func (tc *TOTPValidator) ValidateSignedCounter(counter int64, code int, mac []byte) (bool, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if len(tc.CounterKey) == 0 {
+		return false, ErrMissingCounterKey
+	}
+
+	if !hmac.Equal(SignCounter(tc.CounterKey, counter), mac) {
+		return false, ErrInvalidCounterMAC
+	}
+
+	if counter <= int64(tc.LastT) || tc.used(int(counter)) {
+		return false, nil
+	}
+
+	now := tc.clock().Now()
+	if !tc.matchesAny(now, counter, code) {
+		return false, nil
+	}
+
+	tc.recordUse(now, int(counter))
+	return true, nil
+}
+
+// matchesAny returns whether code is the code for value under any key accepted at now,
+// comparing against every key in constant time.
+func (tc *TOTPValidator) matchesAny(now time.Time, value int64, code int) bool {
+	matched := 0
+	for _, g := range tc.generators(now) {
+		matched |= codesEqual(g.code(value), code)
+	}
+	return matched == 1
+}
+
+// SignRequest returns the HMAC-SHA256 of code and clientTime, the client's Unix time in
+// seconds, under sigKey as expected by TOTPValidator.ValidateSignedRequest.
+func SignRequest(sigKey []byte, code int, clientTime int64) []byte {
+	h := hmac.New(sha256.New, sigKey)
+
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], uint64(code))
+	binary.BigEndian.PutUint64(buf[8:], uint64(clientTime))
+	h.Write(buf[:])
+
+	return h.Sum(nil)
+}
+
+// ValidateSignedRequest validates a code submitted along with the client's Unix time in
+// seconds and a signature over both produced by SignRequest under sigKey. Binding the code
+// to the time it was generated prevents a relay from replaying it at a different time.
+// The signature is verified first, then clientTime must lie within the validator's past and
+// future tolerance of serverNow and finally code must be valid for the time step containing
+// clientTime. Steps at or before LastT or seen by ReplayStore are rejected and an
+// accepted step is consumed as for ValidateStringCT.
+// The returned T has the same meaning as for ValidateTOTPCode. An error is returned if sigKey
+// is empty, the signature does not verify or clientTime is out of range.
+func (tc *TOTPValidator) ValidateSignedRequest(serverNow time.Time, code int, clientTime int64, sig []byte, sigKey []byte) (bool, int, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	stepSize := tc.stepSize()
+	current := tc.stepAt(stepSize, serverNow)
+
+	if len(sigKey) == 0 {
+		return false, current, ErrMissingSignatureKey
+	}
+
+	if !hmac.Equal(SignRequest(sigKey, code, clientTime), sig) {
+		return false, current, ErrInvalidRequestSignature
+	}
+
+	if clientTime < serverNow.Add(-tc.pastTolerance()).Unix() || clientTime > serverNow.Add(tc.futureTolerance()).Unix() {
+		return false, current, ErrClientTimeOutOfRange
+	}
+
+	t := tc.stepAt(stepSize, time.Unix(clientTime, 0))
+	if tc.used(t) {
+		return false, current, nil
+	}
+
+	if !tc.matchesAny(serverNow, int64(t), code) {
+		return false, current, nil
+	}
+
+	tc.recordUse(serverNow, t)
+	return true, t, nil
+}
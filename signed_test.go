@@ -0,0 +1,101 @@
+package otp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateSignedCounter(t *testing.T) {
+	counterKey := []byte("counter signing key")
+
+	tests := []struct {
+		Name       string
+		Counter    int64
+		Code       int
+		MAC        []byte
+		CounterKey []byte
+		LastT      int
+		Match      bool
+		Err        error
+	}{
+		{"Match", 1, 287082, SignCounter(counterKey, 1), counterKey, 0, true, nil},
+		{"No Match", 1, 287083, SignCounter(counterKey, 1), counterKey, 0, false, nil},
+		{"Other Counter Code", 2, 287082, SignCounter(counterKey, 2), counterKey, 0, false, nil},
+		{"MAC For Other Counter", 1, 287082, SignCounter(counterKey, 2), counterKey, 0, false, ErrInvalidCounterMAC},
+		{"MAC Under Other Key", 1, 287082, SignCounter([]byte("other"), 1), counterKey, 0, false, ErrInvalidCounterMAC},
+		{"Missing MAC", 1, 287082, nil, counterKey, 0, false, ErrInvalidCounterMAC},
+		{"Missing Counter Key", 1, 287082, SignCounter(counterKey, 1), nil, 0, false, ErrMissingCounterKey},
+		{"LastT", 1, 287082, SignCounter(counterKey, 1), counterKey, 1, false, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:        []byte("12345678901234567890"),
+				CounterKey: test.CounterKey,
+				LastT:      test.LastT,
+			}
+
+			match, err := validator.ValidateSignedCounter(test.Counter, test.Code, test.MAC)
+			if err != test.Err {
+				t.Errorf("Error did not match. Expected %v and got %v.\n", test.Err, err)
+			}
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+		})
+	}
+}
+
+func TestValidateSignedRequest(t *testing.T) {
+	sigKey := []byte("request signing key")
+	serverNow := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name       string
+		Code       int
+		ClientTime int64
+		Sig        []byte
+		SigKey     []byte
+		LastT      int
+		Match      bool
+		T          int
+		Err        error
+	}{
+		{"Match", 7081804, 1111111109, SignRequest(sigKey, 7081804, 1111111109), sigKey, 0, true, 0x23523EC, nil},
+		{"Match Client Behind", 89731029, 1111111079, SignRequest(sigKey, 89731029, 1111111079), sigKey, 0, true, 0x23523EB, nil},
+		{"Match Client Ahead", 14050471, 1111111111, SignRequest(sigKey, 14050471, 1111111111), sigKey, 0, true, 0x23523ED, nil},
+		{"Code For Other Time", 7081804, 1111111079, SignRequest(sigKey, 7081804, 1111111079), sigKey, 0, false, 0x23523EC, nil},
+		{"No Match", 7081803, 1111111109, SignRequest(sigKey, 7081803, 1111111109), sigKey, 0, false, 0x23523EC, nil},
+		{"Too Far Behind", 48150727, 1111111078, SignRequest(sigKey, 48150727, 1111111078), sigKey, 0, false, 0x23523EC, ErrClientTimeOutOfRange},
+		{"Too Far Ahead", 44266759, 1111111140, SignRequest(sigKey, 44266759, 1111111140), sigKey, 0, false, 0x23523EC, ErrClientTimeOutOfRange},
+		{"Retimed", 7081804, 1111111108, SignRequest(sigKey, 7081804, 1111111109), sigKey, 0, false, 0x23523EC, ErrInvalidRequestSignature},
+		{"Other Code", 7081805, 1111111109, SignRequest(sigKey, 7081804, 1111111109), sigKey, 0, false, 0x23523EC, ErrInvalidRequestSignature},
+		{"Other Key", 7081804, 1111111109, SignRequest([]byte("other"), 7081804, 1111111109), sigKey, 0, false, 0x23523EC, ErrInvalidRequestSignature},
+		{"Missing Key", 7081804, 1111111109, SignRequest(sigKey, 7081804, 1111111109), nil, 0, false, 0x23523EC, ErrMissingSignatureKey},
+		{"LastT", 7081804, 1111111109, SignRequest(sigKey, 7081804, 1111111109), sigKey, 0x23523EC, false, 0x23523EC, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+				LastT:           test.LastT,
+			}
+
+			match, tMatch, err := validator.ValidateSignedRequest(serverNow, test.Code, test.ClientTime, test.Sig, test.SigKey)
+			if err != test.Err {
+				t.Errorf("Error did not match. Expected %v and got %v.\n", test.Err, err)
+			}
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
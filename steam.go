@@ -0,0 +1,85 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Steam Guard code format.
+const (
+	steamAlphabet   = "23456789BCDFGHJKMNPQRTVWXY"
+	steamCodeLength = 5
+)
+
+// SteamCode generates a Steam Guard code from a time. It is a SHA1 TOTP whose 31 bit
+// truncated value is written as five characters of Steam's alphabet rather than digits.
+// Steam uses the default 30 second step size. A zero step size uses DefaultStepSizeSeconds.
+func SteamCode(key []byte, stepSizeSeconds int, t time.Time) string {
+	return steamCode(key, int64(TimeStep(stepSizeSeconds, t)))
+}
+
+func steamCode(key []byte, value int64) string {
+	v := truncate(sha1.New, key, value)
+
+	code := make([]byte, steamCodeLength)
+	for i := range code {
+		code[i] = steamAlphabet[v%uint32(len(steamAlphabet))]
+		v /= uint32(len(steamAlphabet))
+	}
+	return string(code)
+}
+
+// DecodeSteamSecret decodes a Steam shared secret, which is base64 rather than the base32
+// used by other authenticators. Surrounding whitespace is ignored and padding is optional.
+func DecodeSteamSecret(s string) ([]byte, error) {
+	secret, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(s), "="))
+	if err != nil {
+		return nil, fmt.Errorf("otp: invalid Steam secret: %w", err)
+	}
+	return secret, nil
+}
+
+// SteamValidator assists in validating a provided Steam Guard code. Its fields have the
+// same meaning as the matching fields of TOTPValidator.
+type SteamValidator struct {
+	Key             []byte
+	StepSizeSeconds int
+	PastTolerance   time.Duration
+	FutureTolerance time.Duration
+	LastT           int
+}
+
+// ValidateSteamCode returns a bool indicating if code is valid for the provided time.
+// Letters are matched ignoring case and surrounding whitespace is ignored. Each step in
+// the window is compared in constant time. The returned T has the same meaning as for
+// TOTPValidator.ValidateTOTPCode. No code is valid for an empty Key.
+func (sv *SteamValidator) ValidateSteamCode(now time.Time, code string) (bool, int) {
+	tc := TOTPValidator{
+		StepSizeSeconds: sv.StepSizeSeconds,
+		PastTolerance:   sv.PastTolerance,
+		FutureTolerance: sv.FutureTolerance,
+		LastT:           sv.LastT,
+	}
+	if len(sv.Key) == 0 {
+		return false, tc.stepAt(tc.stepSize(), now)
+	}
+	input := []byte(strings.ToUpper(strings.TrimSpace(code)))
+
+	matched := 0
+	tMatch := tc.stepAt(tc.stepSize(), now)
+	for _, t := range tc.steps(now) {
+		if tc.used(t) {
+			continue
+		}
+
+		isMatch := subtle.ConstantTimeCompare([]byte(steamCode(sv.Key, int64(t))), input)
+		tMatch = subtle.ConstantTimeSelect(isMatch&^matched, t, tMatch)
+		matched |= isMatch
+	}
+
+	return matched == 1, tMatch
+}
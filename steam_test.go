@@ -0,0 +1,123 @@
+package otp
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSteamCode(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name     string
+		StepSize int
+		Time     time.Time
+		Code     string
+	}{
+		{"RFC 6238 Time", 30, time.Unix(1111111109, 0), "PY4YB"},
+		{"Default Step Size", 0, time.Unix(1111111109, 0), "PY4YB"},
+		{"Next Step", 30, time.Unix(1111111111, 0), "5PP3V"},
+		{"Step One", 30, time.Unix(30, 0), "PV9M4"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			code := SteamCode(key, test.StepSize, test.Time)
+			if code != test.Code {
+				t.Errorf("Code did not match. Expected %s and got %s.\n", test.Code, code)
+			}
+		})
+	}
+}
+
+func TestDecodeSteamSecret(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name   string
+		Secret string
+		Key    []byte
+		Err    string
+	}{
+		{"Padded", "MTIzNDU2Nzg5MDEyMzQ1Njc4OTA=", key, ""},
+		{"Unpadded", "MTIzNDU2Nzg5MDEyMzQ1Njc4OTA", key, ""},
+		{"Whitespace", " MTIzNDU2Nzg5MDEyMzQ1Njc4OTA=\n", key, ""},
+		{"Invalid", "MTIz*DU2", nil, "otp: invalid Steam secret: illegal base64 data at input byte 4"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			secret, err := DecodeSteamSecret(test.Secret)
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !bytes.Equal(secret, test.Key) {
+				t.Errorf("Key did not match. Expected %x and got %x.\n", test.Key, secret)
+			}
+		})
+	}
+}
+
+func TestSteamValidator(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Code            string
+		Match           bool
+		T               int
+		LastT           int
+		PastTolerance   time.Duration
+		FutureTolerance time.Duration
+	}{
+		{"T Match No Window", "PY4YB", true, 0x23523EC, 0, 0, 0},
+		{"T Lowercase", " py4yb ", true, 0x23523EC, 0, 0, 0},
+		{"T No Match", "PY4YC", false, 0x23523EC, 0, 0, 0},
+		{"T-1 Match No Window", "C4VXV", false, 0x23523EC, 0, 0, 0},
+		{"T-1 Match 1 Window", "C4VXV", true, 0x23523EB, 0, 30 * time.Second, 0},
+		{"T+1 Match 1 Window", "5PP3V", true, 0x23523ED, 0, 0, 30 * time.Second},
+		{"T Match LastT", "PY4YB", false, 0x23523EC, 0x23523EC, 30 * time.Second, 30 * time.Second},
+		{"Empty", "", false, 0x23523EC, 0, 30 * time.Second, 30 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &SteamValidator{
+				Key:             []byte("12345678901234567890"),
+				PastTolerance:   test.PastTolerance,
+				FutureTolerance: test.FutureTolerance,
+				LastT:           test.LastT,
+			}
+
+			match, tMatch := validator.ValidateSteamCode(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), test.Code)
+			if match != test.Match {
+				t.Errorf("Match did not match. Expected %t and got %t.\n", test.Match, match)
+			}
+			if tMatch != test.T {
+				t.Errorf("T did not match. Expected %d and got %d.\n", test.T, tMatch)
+			}
+		})
+	}
+}
+
+func TestSteamValidatorEmptyKey(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	for _, key := range [][]byte{nil, {}} {
+		validator := &SteamValidator{Key: key}
+
+		// the code an HMAC with an empty key produces must not be accepted
+		match, tMatch := validator.ValidateSteamCode(now, SteamCode(key, 0, now))
+		if match {
+			t.Error("Code for an empty key matched")
+		}
+		if tMatch != 0x23523EC {
+			t.Errorf("T did not match. Expected %d and got %d.\n", 0x23523EC, tMatch)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package otp
+
+import (
+	"time"
+)
+
+// TimeStep returns the TOTP time step containing t, the counter value T of RFC 6238 that
+// codes are generated from and validators return for use as LastT. A zero step size uses
+// DefaultStepSizeSeconds.
+func TimeStep(stepSizeSeconds int, t time.Time) int {
+	if stepSizeSeconds == 0 {
+		stepSizeSeconds = DefaultStepSizeSeconds
+	}
+	return timeSteps(stepSizeSeconds, t)
+}
+
+// TimeForStep returns the instant time step step begins in UTC. It is the inverse of
+// TimeStep. A zero step size uses DefaultStepSizeSeconds.
+func TimeForStep(stepSizeSeconds int, step int) time.Time {
+	if stepSizeSeconds == 0 {
+		stepSizeSeconds = DefaultStepSizeSeconds
+	}
+	return stepStart(time.Duration(stepSizeSeconds)*time.Second, step).UTC()
+}
+
+// NextStepTime returns the instant the time step following the one containing t begins,
+// which is when the TOTP code for t will change. The result is in t's location.
+func NextStepTime(stepSizeSeconds int, t time.Time) time.Time {
+	if stepSizeSeconds == 0 {
+		stepSizeSeconds = DefaultStepSizeSeconds
+	}
+
+	return TimeForStep(stepSizeSeconds, timeSteps(stepSizeSeconds, t)+1).In(t.Location())
+}
+
+// RemainingSeconds returns the whole seconds left in the time step containing now, from
+// stepSizeSeconds at the start of a step down to 1 in its final second. It is suitable
+// for a countdown to the next code. A zero step size uses DefaultStepSizeSeconds.
+func RemainingSeconds(stepSizeSeconds int, now time.Time) int {
+	return int(NextStepTime(stepSizeSeconds, now).Unix() - now.Unix())
+}
+
+// SecondsElapsed returns the whole seconds since the time step containing now began, from
+// 0 up to stepSizeSeconds-1. It complements RemainingSeconds for a progress bar.
+// A zero step size uses DefaultStepSizeSeconds.
+func SecondsElapsed(stepSizeSeconds int, now time.Time) int {
+	return int(now.Unix() - TimeForStep(stepSizeSeconds, TimeStep(stepSizeSeconds, now)).Unix())
+}
+
+// RemainingDuration is RemainingSeconds with sub-second precision.
+func RemainingDuration(stepSizeSeconds int, now time.Time) time.Duration {
+	return NextStepTime(stepSizeSeconds, now).Sub(now)
+}
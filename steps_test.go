@@ -0,0 +1,110 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"testing"
+	"time"
+)
+
+func TestTimeStep(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		Name     string
+		StepSize int
+		Time     time.Time
+		Step     int
+	}{
+		{"RFC 6238", 30, time.Unix(1111111109, 0), 0x23523EC},
+		{"Default", 0, time.Unix(1111111109, 0), 0x23523EC},
+		{"Step Start", 30, time.Unix(1111111110, 0), 0x23523ED},
+		{"Last Nanosecond", 30, time.Unix(1111111139, 999999999), 0x23523ED},
+		{"Epoch", 30, time.Unix(0, 0), 0},
+		{"Minute", 60, time.Unix(1111111109, 0), 18518518},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			step := TimeStep(test.StepSize, test.Time)
+			if step != test.Step {
+				t.Errorf("Step did not match. Expected %d and got %d.\n", test.Step, step)
+			}
+
+			if test.StepSize == 0 {
+				return
+			}
+			code := TOTPCode(sha1.New, key, EightDigits, test.StepSize, test.Time)
+			if HOTPCode(sha1.New, key, EightDigits, int64(step)) != code {
+				t.Error("Step did not generate the same code as TOTPCode")
+			}
+		})
+	}
+}
+
+func TestTimeForStep(t *testing.T) {
+	tests := []struct {
+		Name     string
+		StepSize int
+		Step     int
+		Time     time.Time
+	}{
+		{"RFC 6238", 30, 0x23523EC, time.Date(2005, 3, 18, 1, 58, 0, 0, time.UTC)},
+		{"Default", 0, 0x23523EC, time.Date(2005, 3, 18, 1, 58, 0, 0, time.UTC)},
+		{"First Step", 30, 1, time.Unix(30, 0).UTC()},
+		{"Minute", 60, 18518518, time.Date(2005, 3, 18, 1, 58, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			start := TimeForStep(test.StepSize, test.Step)
+			if start != test.Time {
+				t.Errorf("Time did not match. Expected %s and got %s.\n", test.Time, start)
+			}
+			if step := TimeStep(test.StepSize, start); step != test.Step {
+				t.Errorf("Round trip did not match. Expected %d and got %d.\n", test.Step, step)
+			}
+			if step := TimeStep(test.StepSize, start.Add(-time.Nanosecond)); step != test.Step-1 {
+				t.Errorf("Previous step did not match. Expected %d and got %d.\n", test.Step-1, step)
+			}
+		})
+	}
+}
+
+func TestRemainingSeconds(t *testing.T) {
+	start := time.Date(2005, 3, 18, 1, 58, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name      string
+		StepSize  int
+		Time      time.Time
+		Remaining int
+		Elapsed   int
+		Duration  time.Duration
+	}{
+		{"Step Start", 30, start, 30, 0, 30 * time.Second},
+		{"Middle", 30, start.Add(12 * time.Second), 18, 12, 18 * time.Second},
+		{"Fraction", 30, start.Add(12*time.Second + 250*time.Millisecond), 18, 12, 17750 * time.Millisecond},
+		{"Last Second", 30, start.Add(29*time.Second + 999*time.Millisecond), 1, 29, time.Millisecond},
+		{"Default", 0, start.Add(29 * time.Second), 1, 29, time.Second},
+		{"Minute", 60, start.Add(29 * time.Second), 31, 29, 31 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			remaining := RemainingSeconds(test.StepSize, test.Time)
+			if remaining != test.Remaining {
+				t.Errorf("Remaining did not match. Expected %d and got %d.\n", test.Remaining, remaining)
+			}
+
+			elapsed := SecondsElapsed(test.StepSize, test.Time)
+			if elapsed != test.Elapsed {
+				t.Errorf("Elapsed did not match. Expected %d and got %d.\n", test.Elapsed, elapsed)
+			}
+
+			duration := RemainingDuration(test.StepSize, test.Time)
+			if duration != test.Duration {
+				t.Errorf("Duration did not match. Expected %s and got %s.\n", test.Duration, duration)
+			}
+		})
+	}
+}
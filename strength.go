@@ -0,0 +1,77 @@
+package otp
+
+import (
+	"math"
+)
+
+// RecommendedSecretBits is the minimum secret entropy accepted by SecretStrength, the 128
+// bits recommended by RFC 4226 for HMAC-SHA1.
+const RecommendedSecretBits = 128
+
+// StrengthReport describes the strength of a secret as estimated by SecretStrength.
+type StrengthReport struct {
+	// Length is the secret's length in bytes.
+	Length int
+	// Bits is the estimated entropy of the secret.
+	Bits float64
+	// Acceptable is whether Bits meets RecommendedSecretBits.
+	Acceptable bool
+}
+
+// SecretStrength estimates the entropy of key to flag weak secrets, such as short ones or
+// those repeating a few byte values, when they are entered or imported.
+// The estimate is deliberately simple. A key repeating no more byte values than a random
+// key of its length plausibly would is credited the 8 bits per byte a random key carries,
+// so random keys of MinSecretBytes are acceptable despite the odd repeated byte. Other
+// keys score 8 bits per byte scaled by the Shannon entropy of their byte frequencies
+// relative to the most their length allows, so a key of one repeated byte scores 0. It
+// can't detect structure such as sequences or text, so it only identifies keys that are
+// clearly weak and is no proof that a key is random.
+func SecretStrength(key []byte) StrengthReport {
+	report := StrengthReport{Length: len(key)}
+
+	switch len(key) {
+	case 0:
+	case 1:
+		report.Bits = 8
+	default:
+		var counts [256]int
+		distinct := 0
+		for _, b := range key {
+			if counts[b] == 0 {
+				distinct++
+			}
+			counts[b]++
+		}
+
+		if !hasRepeatPattern(len(key), distinct) {
+			report.Bits = 8 * float64(len(key))
+			break
+		}
+
+		entropy := 0.0
+		for _, count := range counts {
+			if count > 0 {
+				p := float64(count) / float64(len(key))
+				entropy -= p * math.Log2(p)
+			}
+		}
+
+		maxEntropy := math.Log2(math.Min(float64(len(key)), 256))
+		report.Bits = 8 * float64(len(key)) * entropy / maxEntropy
+	}
+
+	report.Acceptable = report.Bits >= RecommendedSecretBits
+	return report
+}
+
+// hasRepeatPattern returns whether a key of length bytes with distinct byte values repeats
+// bytes far more often than a random key would. A random key is expected to repeat
+// length-256(1-(255/256)^length) bytes; a margin of four standard deviations of the
+// roughly Poisson repeat count, plus four, makes a random key exceeding it implausible.
+func hasRepeatPattern(length, distinct int) bool {
+	expectedDistinct := 256 * (1 - math.Pow(255.0/256, float64(length)))
+	expectedRepeats := float64(length) - expectedDistinct
+
+	return float64(length-distinct) > expectedRepeats+4*math.Sqrt(expectedRepeats)+4
+}
@@ -0,0 +1,63 @@
+package otp
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestSecretStrength(t *testing.T) {
+	distinct := make([]byte, 512)
+	for i := range distinct {
+		distinct[i] = byte(i)
+	}
+
+	tests := []struct {
+		Name       string
+		Key        []byte
+		Bits       float64
+		Acceptable bool
+	}{
+		{"Distinct 20 Bytes", distinct[:20], 160, true},
+		{"Distinct 16 Bytes", distinct[:16], 128, true},
+		{"Distinct 10 Bytes", distinct[:10], 80, false},
+		{"16 Bytes One Repeat", append(append([]byte(nil), distinct[:15]...), 0), 128, true},
+		{"16 Bytes Eight Repeats", append(append([]byte(nil), distinct[:8]...), distinct[:8]...), 96, false},
+		{"Every Value Twice", distinct, 4096, true},
+		{"RFC 4226 Test Key", []byte("12345678901234567890"), 122.9795, false},
+		{"Repeated Byte", bytes.Repeat([]byte{0x42}, 32), 0, false},
+		{"Two Values", bytes.Repeat([]byte{0, 1}, 16), 256 / 5.0, false},
+		{"Single Byte", []byte{1}, 8, false},
+		{"Empty", nil, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			report := SecretStrength(test.Key)
+			if report.Length != len(test.Key) {
+				t.Errorf("Length did not match. Expected %d and got %d.\n", len(test.Key), report.Length)
+			}
+			if math.Abs(report.Bits-test.Bits) > 0.0001 {
+				t.Errorf("Bits did not match. Expected %f and got %f.\n", test.Bits, report.Bits)
+			}
+			if report.Acceptable != test.Acceptable {
+				t.Errorf("Acceptable did not match. Expected %t and got %t.\n", test.Acceptable, report.Acceptable)
+			}
+		})
+	}
+}
+
+func TestSecretStrengthGenerated(t *testing.T) {
+	// generated secrets are acceptable unless they repeat an implausible number of bytes
+	for _, size := range []int{MinSecretBytes, DefaultSecretBytes, 32, 64} {
+		for i := 0; i < 10000; i++ {
+			secret, err := GenerateSecret(size)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if report := SecretStrength(secret); !report.Acceptable || report.Bits != float64(8*size) {
+				t.Fatalf("Generated %d byte secret %x scored %f bits.\n", size, secret, report.Bits)
+			}
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// URI schemes recognised by ExtractURIs.
+const (
+	uriScheme          = "otpauth://"
+	migrationURIScheme = "otpauth-migration://"
+)
+
+// ExtractURIs returns every otpauth:// and otpauth-migration:// URI found in text, in
+// the order they appear. It is intended for input pasted by users where the URI is
+// surrounded by other text. A URI ends at whitespace, a quote or an angle bracket, and
+// trailing punctuation such as a full stop or closing parenthesis is dropped. Scheme
+// matching is case-insensitive and percent-encoded characters are returned unchanged.
+// The otpauth:// URIs can be passed to ParseURI or ParseKeyURI.
+func ExtractURIs(text string) []string {
+	var uris []string
+
+	for i := 0; i < len(text); i++ {
+		var scheme string
+		switch {
+		case hasPrefixFold(text[i:], uriScheme):
+			scheme = uriScheme
+		case hasPrefixFold(text[i:], migrationURIScheme):
+			scheme = migrationURIScheme
+		default:
+			continue
+		}
+
+		end := i + len(scheme)
+		for end < len(text) && !isURITerminator(text[end]) {
+			end++
+		}
+
+		uri := strings.TrimRight(text[i:end], ".,;:!?)]}")
+		if len(uri) > len(scheme) {
+			uris = append(uris, uri)
+		}
+
+		i = end - 1
+	}
+
+	return uris
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+func isURITerminator(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f', '\v', '"', '\'', '`', '<', '>':
+		return true
+	}
+	return false
+}
+
+// ParseURI parses an otpauth:// provisioning URI for a TOTP key, as produced by the QR
+// codes used to enroll authenticator apps, into a validator along with the issuer and
+// account name from its label:
+//
+//	otpauth://totp/Issuer:account?secret=...&issuer=Issuer&algorithm=SHA1&digits=6&period=30
+//
+// The URI is parsed as by ParseKeyURI. hotp and Steam URIs are not supported.
+func ParseURI(uri string) (*TOTPValidator, string, string, error) {
+	k, err := ParseKeyURI(uri)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if k.HOTP {
+		return nil, "", "", errors.New("otp: hotp URIs are not supported")
+	}
+	if k.Steam {
+		return nil, "", "", errors.New("otp: Steam URIs are not supported")
+	}
+
+	validator := &TOTPValidator{
+		Key:             k.Key,
+		HashProvider:    k.HashProvider,
+		Digits:          k.Digits,
+		StepSizeSeconds: k.StepSizeSeconds,
+	}
+	return validator, k.Issuer, k.Account, nil
+}
+
+// ParseKeyURI parses an otpauth:// provisioning URI for a TOTP or HOTP key into a KeyURI,
+// the inverse of KeyURI.String. The secret is required, as is the counter of an hotp URI.
+// Missing algorithm, digits and period parameters default to SHA1, six digits and a 30
+// second period per the Key URI format and are set explicitly in the returned KeyURI. Any
+// digits accepted by NewDigits are supported, including the non-standard 4 and 9 digits
+// some tokens use. The issuer parameter takes precedence over an issuer prefix in the label.
+func ParseKeyURI(uri string) (*KeyURI, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("otp: invalid URI: %w", err)
+	}
+
+	if !strings.EqualFold(u.Scheme, "otpauth") {
+		return nil, fmt.Errorf("otp: unsupported URI scheme %q", u.Scheme)
+	}
+
+	k := &KeyURI{
+		HashProvider:    sha1.New,
+		Digits:          SixDigits,
+		StepSizeSeconds: DefaultStepSizeSeconds,
+	}
+
+	switch strings.ToLower(u.Host) {
+	case "totp":
+	case "hotp":
+		k.HOTP = true
+	default:
+		return nil, fmt.Errorf("otp: unsupported OTP type %q", u.Host)
+	}
+
+	k.Issuer, k.Account = parseLabel(strings.TrimPrefix(u.EscapedPath(), "/"))
+
+	params := u.Query()
+	if p := params.Get("issuer"); p != "" {
+		k.Issuer = p
+	}
+
+	secret := params.Get("secret")
+	if secret == "" {
+		return nil, errors.New("otp: URI has no secret")
+	}
+	if k.Key, err = DecodeSecret(secret); err != nil {
+		return nil, err
+	}
+
+	if p := params.Get("algorithm"); p != "" {
+		hashProvider, err := HashProviderByName(p)
+		if err != nil {
+			return nil, err
+		}
+		k.HashProvider = hashProvider
+	}
+
+	if p := params.Get("digits"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("otp: invalid digits %q", p)
+		}
+		if k.Digits, err = NewDigits(n); err != nil {
+			return nil, err
+		}
+	}
+
+	if p := params.Get("period"); p != "" {
+		period, err := strconv.Atoi(p)
+		if err != nil || period <= 0 {
+			return nil, fmt.Errorf("otp: invalid period %q", p)
+		}
+		k.StepSizeSeconds = period
+	}
+
+	if k.HOTP {
+		p := params.Get("counter")
+		if p == "" {
+			return nil, errors.New("otp: hotp URI has no counter")
+		}
+		if k.Counter, err = strconv.ParseInt(p, 10, 64); err != nil || k.Counter < 0 {
+			return nil, fmt.Errorf("otp: invalid counter %q", p)
+		}
+	}
+
+	switch p := params.Get("encoder"); strings.ToLower(p) {
+	case "":
+	case "steam":
+		k.Steam = true
+	default:
+		return nil, fmt.Errorf("otp: unsupported encoder %q", p)
+	}
+
+	k.Image = params.Get("image")
+
+	return k, nil
+}
+
+// parseLabel splits an escaped URI label of the form "Issuer:account" or "account" and
+// unescapes its parts. The issuer is separated by the first unescaped colon so an issuer
+// or account written by KeyURI.String with an escaped colon is preserved. A label without
+// an unescaped colon is split on its first escaped one as some apps escape the separator.
+func parseLabel(escaped string) (string, string) {
+	if i := strings.Index(escaped, ":"); i >= 0 {
+		return unescapeLabel(escaped[:i]), unescapeLabel(escaped[i+1:])
+	}
+
+	label := unescapeLabel(escaped)
+	i := strings.Index(label, ":")
+	if i < 0 {
+		return "", label
+	}
+	return strings.TrimSpace(label[:i]), strings.TrimSpace(label[i+1:])
+}
+
+// unescapeLabel unescapes part of a label already validated by url.Parse.
+func unescapeLabel(s string) string {
+	unescaped, err := url.PathUnescape(s)
+	if err != nil {
+		unescaped = s
+	}
+	return strings.TrimSpace(unescaped)
+}
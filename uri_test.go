@@ -0,0 +1,162 @@
+package otp
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExtractURIs(t *testing.T) {
+	tests := []struct {
+		Name string
+		Text string
+		URIs []string
+	}{
+		{"Empty", "", nil},
+		{"No URI", "Here is your key: 2SH3V3GDW7ZNMGYE", nil},
+		{"Only URI", "otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP",
+			[]string{"otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP"}},
+		{"Surrounding Text", "Here is your key: otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP (scan this)",
+			[]string{"otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP"}},
+		{"Trailing Punctuation", "Use otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP.",
+			[]string{"otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP"}},
+		{"Parenthesized", "(otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP)",
+			[]string{"otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP"}},
+		{"Quoted", `href="otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP"`,
+			[]string{"otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP"}},
+		{"Angle Brackets", "<otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP>",
+			[]string{"otpauth://totp/Example:alice?secret=JBSWY3DPEHPK3PXP"}},
+		{"URL Encoded", "key: otpauth://totp/ACME%20Co:john%40example.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME%20Co",
+			[]string{"otpauth://totp/ACME%20Co:john%40example.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME%20Co"}},
+		{"Multiple", "first otpauth://totp/a?secret=AAAA, second otpauth://hotp/b?secret=BBBB&counter=1\nthird",
+			[]string{"otpauth://totp/a?secret=AAAA", "otpauth://hotp/b?secret=BBBB&counter=1"}},
+		{"Migration", "export: otpauth-migration://offline?data=CjEKCkhlbGxvId6tvu8%3D done",
+			[]string{"otpauth-migration://offline?data=CjEKCkhlbGxvId6tvu8%3D"}},
+		{"Uppercase Scheme", "OTPAUTH://TOTP/A?SECRET=AAAA",
+			[]string{"OTPAUTH://TOTP/A?SECRET=AAAA"}},
+		{"Scheme Only", "otpauth:// is the scheme", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			uris := ExtractURIs(test.Text)
+			if !reflect.DeepEqual(uris, test.URIs) {
+				t.Errorf("URIs did not match. Expected %q and got %q.\n", test.URIs, uris)
+			}
+		})
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	tests := []struct {
+		Name     string
+		URI      string
+		Issuer   string
+		Account  string
+		Hash     func() hash.Hash
+		Digits   Digits
+		StepSize int
+		Err      string
+	}{
+		{"Defaults", "otpauth://totp/alice@example.com?secret=" + secret,
+			"", "alice@example.com", sha1.New, SixDigits, 30, ""},
+		{"Label Issuer", "otpauth://totp/Example:alice@example.com?secret=" + secret,
+			"Example", "alice@example.com", sha1.New, SixDigits, 30, ""},
+		{"Encoded Label", "otpauth://totp/ACME%20Co:%20john.doe%40email.com?secret=" + secret,
+			"ACME Co", "john.doe@email.com", sha1.New, SixDigits, 30, ""},
+		{"Encoded Colon", "otpauth://totp/ACME%20Co%3Ajohn?secret=" + secret,
+			"ACME Co", "john", sha1.New, SixDigits, 30, ""},
+		{"Encoded Colon In Issuer", "otpauth://totp/Acme%3AInc:john?secret=" + secret,
+			"Acme:Inc", "john", sha1.New, SixDigits, 30, ""},
+		{"Issuer Param", "otpauth://totp/alice?secret=" + secret + "&issuer=Example%20Inc",
+			"Example Inc", "alice", sha1.New, SixDigits, 30, ""},
+		{"Issuer Param Precedence", "otpauth://totp/Old:alice?secret=" + secret + "&issuer=New",
+			"New", "alice", sha1.New, SixDigits, 30, ""},
+		{"All Params", "otpauth://totp/Example:alice?secret=" + secret + "&algorithm=SHA512&digits=8&period=60",
+			"Example", "alice", sha512.New, EightDigits, 60, ""},
+		{"SHA256", "otpauth://totp/alice?secret=" + secret + "&algorithm=sha256",
+			"", "alice", sha256.New, SixDigits, 30, ""},
+		{"Lowercase Padded Secret", "otpauth://totp/alice?secret=gezdgnbvgy3tqojqgezdgnbvgy3tqojq====",
+			"", "alice", sha1.New, SixDigits, 30, ""},
+		{"Uppercase Scheme", "OTPAUTH://TOTP/alice?secret=" + secret,
+			"", "alice", sha1.New, SixDigits, 30, ""},
+		{"HOTP", "otpauth://hotp/alice?secret=" + secret + "&counter=0",
+			"", "", nil, 0, 0, "otp: hotp URIs are not supported"},
+		{"Steam", "otpauth://totp/alice?secret=" + secret + "&encoder=steam",
+			"", "", nil, 0, 0, "otp: Steam URIs are not supported"},
+		{"Unknown Encoder", "otpauth://totp/alice?secret=" + secret + "&encoder=base26",
+			"", "", nil, 0, 0, `otp: unsupported encoder "base26"`},
+		{"Unknown Type", "otpauth://motp/alice?secret=" + secret,
+			"", "", nil, 0, 0, `otp: unsupported OTP type "motp"`},
+		{"Wrong Scheme", "https://totp/alice?secret=" + secret,
+			"", "", nil, 0, 0, `otp: unsupported URI scheme "https"`},
+		{"Missing Secret", "otpauth://totp/alice",
+			"", "", nil, 0, 0, "otp: URI has no secret"},
+		{"Bad Secret", "otpauth://totp/alice?secret=1NVALID",
+			"", "", nil, 0, 0, `otp: invalid character '1' at position 0 in secret`},
+		{"Unknown Algorithm", "otpauth://totp/alice?secret=" + secret + "&algorithm=MD5",
+			"", "", nil, 0, 0, `otp: unsupported algorithm "MD5"`},
+		{"Bad Digits", "otpauth://totp/alice?secret=" + secret + "&digits=six",
+			"", "", nil, 0, 0, `otp: invalid digits "six"`},
+		{"Unsupported Digits", "otpauth://totp/alice?secret=" + secret + "&digits=12",
+			"", "", nil, 0, 0, "otp: unsupported number of digits 12, must be between 1 and 10"},
+		{"Bad Period", "otpauth://totp/alice?secret=" + secret + "&period=0",
+			"", "", nil, 0, 0, `otp: invalid period "0"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator, issuer, account, err := ParseURI(test.URI)
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Error did not match. Expected %q and got %v.\n", test.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if issuer != test.Issuer {
+				t.Errorf("Issuer did not match. Expected %q and got %q.\n", test.Issuer, issuer)
+			}
+			if account != test.Account {
+				t.Errorf("Account did not match. Expected %q and got %q.\n", test.Account, account)
+			}
+			if !bytes.Equal(validator.Key, []byte("12345678901234567890")) {
+				t.Errorf("Key did not match. Got %q.\n", validator.Key)
+			}
+			if validator.Digits != test.Digits {
+				t.Errorf("Digits did not match. Expected %d and got %d.\n", test.Digits, validator.Digits)
+			}
+			if validator.StepSizeSeconds != test.StepSize {
+				t.Errorf("Step size did not match. Expected %d and got %d.\n", test.StepSize, validator.StepSizeSeconds)
+			}
+			if !bytes.Equal(validator.HashProvider().Sum(nil), test.Hash().Sum(nil)) {
+				t.Error("Hash provider did not match")
+			}
+		})
+	}
+}
+
+func TestParseURIValidates(t *testing.T) {
+	validator, _, _, err := ParseURI("otpauth://totp/Example:alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&digits=8")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ok, tMatch := validator.ValidateTOTPCode(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), 7081804)
+	if !ok {
+		t.Error("Code did not match")
+	}
+	if tMatch != 0x23523EC {
+		t.Errorf("T did not match. Expected %d and got %d.\n", 0x23523EC, tMatch)
+	}
+}
@@ -0,0 +1,41 @@
+package otp
+
+// Verifier validates codes without exposing whether they are time or counter based, so
+// an authentication layer can accept either and be tested with a VerifierFunc.
+// Validate returns whether code is valid and state which the caller should persist
+// for the next validation:
+//
+//   - For a TOTPValidator, state is the matched time step T, to be stored as LastT so the
+//     code can't be reused, or the current time step when code is invalid. Codes are
+//     validated at the time of the validator's Clock.
+//   - For an HOTPValidator adapted with AsVerifier, state is the next expected counter,
+//     to be stored as Counter, which is unchanged when code is invalid.
+type Verifier interface {
+	Validate(code int) (ok bool, state int)
+}
+
+// VerifierFunc adapts a function to a Verifier, for example to fake validation in tests.
+type VerifierFunc func(code int) (bool, int)
+
+// Validate calls f(code).
+func (f VerifierFunc) Validate(code int) (bool, int) {
+	return f(code)
+}
+
+var _ Verifier = (*TOTPValidator)(nil)
+
+// AsVerifier returns hv as a Verifier. HOTPValidator.Validate returns its counter as an
+// int64, so the Verifier converts it to an int. On 32 bit platforms counters must remain
+// below math.MaxInt32 to be represented.
+func (hv *HOTPValidator) AsVerifier() Verifier {
+	return hotpVerifier{hv}
+}
+
+type hotpVerifier struct {
+	hv *HOTPValidator
+}
+
+func (v hotpVerifier) Validate(code int) (bool, int) {
+	ok, counter := v.hv.Validate(code)
+	return ok, int(counter)
+}
@@ -0,0 +1,38 @@
+package otp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifier(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name     string
+		Verifier Verifier
+		Code     int
+		OK       bool
+		State    int
+	}{
+		{"TOTP", &TOTPValidator{Key: key, Clock: NewFakeClock(now)}, 81804, true, 0x23523EC},
+		{"TOTP Invalid", &TOTPValidator{Key: key, Clock: NewFakeClock(now)}, 123456, false, 0x23523EC},
+		{"HOTP", (&HOTPValidator{Key: key, Counter: 3}).AsVerifier(), rfc4226Vectors[3], true, 4},
+		{"HOTP Look Ahead", (&HOTPValidator{Key: key, Counter: 3, LookAhead: 2}).AsVerifier(), rfc4226Vectors[5], true, 6},
+		{"HOTP Invalid", (&HOTPValidator{Key: key, Counter: 3}).AsVerifier(), rfc4226Vectors[4], false, 3},
+		{"Func", VerifierFunc(func(code int) (bool, int) { return code == 42, 7 }), 42, true, 7},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ok, state := test.Verifier.Validate(test.Code)
+			if ok != test.OK {
+				t.Errorf("OK did not match. Expected %t and got %t.\n", test.OK, ok)
+			}
+			if state != test.State {
+				t.Errorf("State did not match. Expected %d and got %d.\n", test.State, state)
+			}
+		})
+	}
+}
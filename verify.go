@@ -0,0 +1,40 @@
+package otp
+
+import (
+	"time"
+)
+
+// VerifyResult describes the outcome of TOTPValidator.Verify.
+// MatchedT is the time step code matched and is only set when Valid. CurrentT is the time
+// step containing the validation time. Drift is MatchedT - CurrentT, negative for a code
+// from the past and positive for one from the future, and is 0 when not Valid.
+type VerifyResult struct {
+	Valid    bool
+	MatchedT int
+	CurrentT int
+	Drift    int
+}
+
+// Verify validates code like ValidateTOTPCode but returns the matched and current time
+// steps separately along with the drift between them. Monitoring drift across users helps
+// detect clock skew.
+func (tc *TOTPValidator) Verify(now time.Time, code int) VerifyResult {
+	result := VerifyResult{CurrentT: tc.stepAt(tc.stepSize(), now)}
+
+	ok, t := tc.ValidateTOTPCode(now, code)
+	if ok {
+		result.Valid = true
+		result.MatchedT = t
+		result.Drift = t - result.CurrentT
+	}
+	return result
+}
+
+// VerifySkew validates code like ValidateTOTPCode, returning the client's clock skew in
+// time steps: the matched step minus the current step, 0 when in sync, negative for a
+// client behind and positive for one ahead. skewSteps is only meaningful when ok is true
+// and is 0 otherwise. It is Verify reduced to its Drift.
+func (tc *TOTPValidator) VerifySkew(now time.Time, code int) (ok bool, skewSteps int) {
+	result := tc.Verify(now, code)
+	return result.Valid, result.Drift
+}
@@ -0,0 +1,71 @@
+package otp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Code   int
+		LastT  int
+		Result VerifyResult
+	}{
+		{"T", 7081804, 0, VerifyResult{true, 0x23523EC, 0x23523EC, 0}},
+		{"T-1", 89731029, 0, VerifyResult{true, 0x23523EB, 0x23523EC, -1}},
+		{"T+1", 14050471, 0, VerifyResult{true, 0x23523ED, 0x23523EC, 1}},
+		{"No Match", 7081803, 0, VerifyResult{false, 0, 0x23523EC, 0}},
+		{"LastT", 7081804, 0x23523EC, VerifyResult{false, 0, 0x23523EC, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastTolerance:   30 * time.Second,
+				FutureTolerance: 30 * time.Second,
+				LastT:           test.LastT,
+			}
+
+			result := validator.Verify(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), test.Code)
+			if result != test.Result {
+				t.Errorf("Result did not match. Expected %+v and got %+v.\n", test.Result, result)
+			}
+		})
+	}
+}
+
+func TestVerifySkew(t *testing.T) {
+	tests := []struct {
+		Name string
+		Code int
+		OK   bool
+		Skew int
+	}{
+		{"In Sync", 7081804, true, 0},
+		{"Behind", 89731029, true, -1},
+		{"Ahead Two", 44266759, true, 2},
+		{"No Match", 7081803, false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				PastTolerance:   60 * time.Second,
+				FutureTolerance: 60 * time.Second,
+			}
+
+			ok, skew := validator.VerifySkew(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), test.Code)
+			if ok != test.OK {
+				t.Errorf("OK did not match. Expected %t and got %t.\n", test.OK, ok)
+			}
+			if skew != test.Skew {
+				t.Errorf("Skew did not match. Expected %d and got %d.\n", test.Skew, skew)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package otp
+
+import (
+	"time"
+)
+
+// WindowClamped returns whether MaxWindowSteps narrows the validator's tolerance window at
+// now. When it does, the steps covered by the tolerances furthest from the current step
+// are not accepted and WindowBounds reports the narrower window. It is always false with
+// AcceptOffsets, which replace the window.
+func (tc *TOTPValidator) WindowClamped(now time.Time) bool {
+	if len(tc.AcceptOffsets) > 0 {
+		return false
+	}
+
+	stepSize := tc.stepSize()
+	tMin, tMax := tc.toleranceWindow(stepSize, now)
+	first, last := tc.clampWindow(tc.stepAt(stepSize, now), tMin, tMax)
+	return first != tMin || last != tMax
+}
+
+// maxWindowSteps returns the cap on the number of steps in the tolerance window, or 0 if
+// there is no cap.
+func (tc *TOTPValidator) maxWindowSteps() int {
+	switch {
+	case tc.MaxWindowSteps == 0:
+		return DefaultMaxWindowSteps
+	case tc.MaxWindowSteps < 0:
+		return 0
+	}
+	return tc.MaxWindowSteps
+}
+
+// clampWindow narrows the window tMin to tMax around the current step to at most
+// maxWindowSteps steps. Steps are kept alternately either side of current, nearest first,
+// so a window that is only wide on one side keeps as many steps as possible on that side.
+func (tc *TOTPValidator) clampWindow(current, tMin, tMax int) (int, int) {
+	max := tc.maxWindowSteps()
+	if max == 0 || tMax-tMin+1 <= max {
+		return tMin, tMax
+	}
+
+	// the current step is always in the window
+	if current < tMin {
+		current = tMin
+	}
+	if current > tMax {
+		current = tMax
+	}
+
+	budget := max - 1
+	past := minInt(current-tMin, (budget+1)/2)
+	future := minInt(tMax-current, budget-past)
+	past = minInt(current-tMin, budget-future)
+
+	return current - past, current + future
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
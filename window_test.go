@@ -0,0 +1,197 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"hash"
+	"testing"
+	"time"
+)
+
+func TestClampWindow(t *testing.T) {
+	tests := []struct {
+		Name           string
+		MaxWindowSteps int
+		TMin           int
+		TMax           int
+		ExpectedMin    int
+		ExpectedMax    int
+	}{
+		{"Within Default", 0, 95, 104, 95, 104},
+		{"Default", 0, 0, 200, 95, 104},
+		{"Symmetric", 5, 90, 110, 98, 102},
+		{"Past Only", 5, 0, 100, 96, 100},
+		{"Future Only", 5, 100, 200, 100, 104},
+		{"Short Past", 5, 99, 200, 99, 103},
+		{"Short Future", 5, 0, 101, 97, 101},
+		{"Single Step", 1, 0, 200, 100, 100},
+		{"Uncapped", -1, 0, 200, 0, 200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{MaxWindowSteps: test.MaxWindowSteps}
+
+			tMin, tMax := validator.clampWindow(100, test.TMin, test.TMax)
+			if tMin != test.ExpectedMin || tMax != test.ExpectedMax {
+				t.Errorf("Window did not match. Expected %d-%d and got %d-%d.\n", test.ExpectedMin, test.ExpectedMax, tMin, tMax)
+			}
+		})
+	}
+}
+
+// summingHash counts the calls to Sum of the hash it wraps.
+type summingHash struct {
+	hash.Hash
+	sums *int
+}
+
+func (h summingHash) Sum(b []byte) []byte {
+	*h.sums++
+	return h.Hash.Sum(b)
+}
+
+func TestMaxWindowStepsBoundsWork(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+	years := 3 * 365 * 24 * time.Hour
+
+	sums := 0
+	validator := &TOTPValidator{
+		Key: []byte("12345678901234567890"),
+		HashProvider: func() hash.Hash {
+			return summingHash{sha1.New(), &sums}
+		},
+		Digits:          EightDigits,
+		PastTolerance:   years,
+		FutureTolerance: years,
+	}
+
+	if ok, _ := validator.ValidateTOTPCode(now, 7081803); ok {
+		t.Error("Code matched")
+	}
+	// each HMAC sums the inner and outer hash
+	if hmacs := sums / 2; hmacs != DefaultMaxWindowSteps {
+		t.Errorf("HMAC computations did not match. Expected %d and got %d.\n", DefaultMaxWindowSteps, hmacs)
+	}
+
+	if ok, tMatch := validator.ValidateTOTPCode(now, 14050471); !ok || tMatch != 0x23523ED {
+		t.Errorf("Code near the current step did not match. Got %t and %d.\n", ok, tMatch)
+	}
+
+	tMin, tMax := validator.WindowBounds(now)
+	if tMax-tMin+1 != DefaultMaxWindowSteps {
+		t.Errorf("Window size did not match. Expected %d and got %d.\n", DefaultMaxWindowSteps, tMax-tMin+1)
+	}
+	if profile := validator.SecurityProfile(); profile.AcceptedStepsPerAttempt != DefaultMaxWindowSteps {
+		t.Errorf("Accepted steps did not match. Expected %d and got %d.\n", DefaultMaxWindowSteps, profile.AcceptedStepsPerAttempt)
+	}
+}
+
+func TestWithMaxWindowSteps(t *testing.T) {
+	validator, err := NewTOTPValidator([]byte("12345678901234567890"),
+		WithPastSteps(5),
+		WithFutureSteps(5),
+		WithMaxWindowSteps(3),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tMin, tMax := validator.WindowBounds(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC))
+	if tMin != 0x23523EB || tMax != 0x23523ED {
+		t.Errorf("Bounds did not match. Expected %d-%d and got %d-%d.\n", 0x23523EB, 0x23523ED, tMin, tMax)
+	}
+}
+
+func TestNegativeTolerance(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name     string
+		Validate func(tc *TOTPValidator) bool
+	}{
+		{"ValidateTOTPCode", func(tc *TOTPValidator) bool {
+			ok, _ := tc.ValidateTOTPCode(now, 7081804)
+			return ok
+		}},
+		{"VerifyAndConsume", func(tc *TOTPValidator) bool {
+			return tc.VerifyAndConsume(now, 7081804)
+		}},
+		{"Verify", func(tc *TOTPValidator) bool {
+			return tc.Verify(now, 7081804).Valid
+		}},
+		{"ValidUntil", func(tc *TOTPValidator) bool {
+			_, ok := tc.ValidUntil(now, 7081804)
+			return ok
+		}},
+		{"ValidateTOTPCodeErr", func(tc *TOTPValidator) bool {
+			_, err := tc.ValidateTOTPCodeErr(now, 7081804)
+			return err == nil
+		}},
+		{"ValidateBetween", func(tc *TOTPValidator) bool {
+			ok, _ := tc.ValidateBetween(now, now.Add(time.Second), 7081804)
+			return ok
+		}},
+		{"FixedScanSteps", func(tc *TOTPValidator) bool {
+			tc.FixedScanSteps = 5
+			ok, _ := tc.ValidateTOTPCode(now, 7081804)
+			return ok
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				Digits:          EightDigits,
+				FutureTolerance: -time.Minute,
+			}
+
+			if test.Validate(validator) {
+				t.Error("Code matched an empty window")
+			}
+		})
+	}
+
+	validator := &TOTPValidator{Key: []byte("12345678901234567890"), FutureTolerance: -time.Minute}
+	if tMin, tMax := validator.WindowBounds(now); tMax >= tMin {
+		t.Errorf("Bounds were not empty. Got %d-%d.\n", tMin, tMax)
+	}
+	if steps := validator.SecurityProfile().AcceptedStepsPerAttempt; steps != 0 {
+		t.Errorf("Steps did not match. Expected 0 and got %d.\n", steps)
+	}
+}
+
+func TestWindowClamped(t *testing.T) {
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	tests := []struct {
+		Name           string
+		PastTolerance  time.Duration
+		MaxWindowSteps int
+		Offsets        []int
+		Clamped        bool
+	}{
+		{"Within Cap", 30 * time.Second, 0, nil, false},
+		{"Exactly Cap", 4 * time.Minute, 0, nil, false},
+		{"Over Cap", 10 * time.Minute, 0, nil, true},
+		{"Over Custom Cap", 30 * time.Second, 2, nil, true},
+		{"Uncapped", 10 * time.Minute, -1, nil, false},
+		{"Offsets", 10 * time.Minute, 2, []int{-5, 0, 5}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator := &TOTPValidator{
+				Key:             []byte("12345678901234567890"),
+				PastTolerance:   test.PastTolerance,
+				FutureTolerance: 30 * time.Second,
+				MaxWindowSteps:  test.MaxWindowSteps,
+				AcceptOffsets:   test.Offsets,
+			}
+
+			if clamped := validator.WindowClamped(now); clamped != test.Clamped {
+				t.Errorf("Clamped did not match. Expected %t and got %t.\n", test.Clamped, clamped)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package otp
+
+// Wipe overwrites b with zeros, for example to clean up a secret returned by DecodeSecret
+// once it has been stored.
+//
+// This is a best-effort measure to reduce how long a secret remains in memory. Go
+// makes no guarantees here: the garbage collector may have moved or copied the
+// backing array, the key may have been copied by the caller or by encoding and
+// decoding steps, and strings derived from the key cannot be wiped at all.
+func Wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// WipeKey overwrites key with zeros. It is the same as Wipe.
+func WipeKey(key []byte) {
+	Wipe(key)
+}
+
+// Wipe overwrites the validator's Key, PreviousKey and CounterKey with zeros, clears them
+// and discards any cached codes derived from them. The validator is unusable afterwards:
+// every code is rejected as the key is empty. See Wipe for the limitations of wiping
+// secrets in Go.
+func (tc *TOTPValidator) Wipe() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	Wipe(tc.Key)
+	tc.Key = nil
+	Wipe(tc.PreviousKey)
+	tc.PreviousKey = nil
+	Wipe(tc.CounterKey)
+	tc.CounterKey = nil
+	tc.index = nil
+}
+
+// Close wipes the validator's keys with Wipe so it can be used where an io.Closer is
+// expected. The validator must not be used after Close. Close always returns nil.
+func (tc *TOTPValidator) Close() error {
+	tc.Wipe()
+	return nil
+}
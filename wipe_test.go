@@ -0,0 +1,83 @@
+package otp
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWipe(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	Wipe(key)
+
+	if !bytes.Equal(key, make([]byte, 20)) {
+		t.Errorf("Key was not wiped: %v", key)
+	}
+
+	// a nil slice is ignored
+	Wipe(nil)
+}
+
+func TestWipeKey(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	WipeKey(key)
+
+	if !bytes.Equal(key, make([]byte, 20)) {
+		t.Errorf("Key was not wiped: %v", key)
+	}
+}
+
+func TestValidatorWipe(t *testing.T) {
+	key := []byte("12345678901234567890")
+	counterKey := []byte("counter key")
+	validator := &TOTPValidator{Key: key, CounterKey: counterKey}
+
+	validator.Wipe()
+
+	if !bytes.Equal(key, make([]byte, 20)) {
+		t.Errorf("Key was not wiped: %v", key)
+	}
+	if validator.Key != nil {
+		t.Error("Key was not cleared")
+	}
+	if !bytes.Equal(counterKey, make([]byte, 11)) {
+		t.Errorf("Counter key was not wiped: %v", counterKey)
+	}
+	if validator.CounterKey != nil {
+		t.Error("Counter key was not cleared")
+	}
+
+	if ok, _ := validator.ValidateTOTPCode(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), 81804); ok {
+		t.Error("Code matched after wiping")
+	}
+}
+
+func TestClose(t *testing.T) {
+	key := []byte("12345678901234567890")
+	previousKey := []byte("abcdefghijklmnopqrst")
+	validator := &TOTPValidator{Key: key, PreviousKey: previousKey}
+
+	validator.ValidateCached(time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC), 81804)
+
+	if err := validator.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if !bytes.Equal(key, make([]byte, 20)) {
+		t.Errorf("Key was not wiped: %v", key)
+	}
+	if validator.Key != nil {
+		t.Error("Key was not cleared")
+	}
+	if !bytes.Equal(previousKey, make([]byte, 20)) {
+		t.Errorf("Previous key was not wiped: %v", previousKey)
+	}
+	if validator.PreviousKey != nil {
+		t.Error("Previous key was not cleared")
+	}
+	if validator.index != nil {
+		t.Error("Cached codes were not cleared")
+	}
+}
@@ -0,0 +1,37 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"time"
+)
+
+// TOTPWords generates a TOTP as wordCount words chosen from wordlist rather than digits,
+// which is easier to read aloud. It uses SHA1 and the default step size.
+// The words are derived from the same 31 bit truncated value as a numeric code by taking
+// successive remainders modulo len(wordlist), so wordCount words may not carry more than
+// 31 bits: len(wordlist)^wordCount must not exceed 2^31. For example a 2048 word list
+// supports up to two words. nil is returned if this is exceeded, if wordlist has fewer
+// than two words or if wordCount is less than one.
+func TOTPWords(key []byte, t time.Time, wordlist []string, wordCount int) []string {
+	if len(wordlist) < 2 || wordCount < 1 {
+		return nil
+	}
+
+	space := uint64(1)
+	for i := 0; i < wordCount; i++ {
+		space *= uint64(len(wordlist))
+		if space > 1<<31 {
+			return nil
+		}
+	}
+
+	value := truncate(sha1.New, key, int64(timeSteps(DefaultStepSizeSeconds, t))) % uint32(space)
+
+	words := make([]string, wordCount)
+	for i := range words {
+		words[i] = wordlist[value%uint32(len(wordlist))]
+		value /= uint32(len(wordlist))
+	}
+
+	return words
+}
@@ -0,0 +1,48 @@
+package otp
+
+import (
+	"crypto/sha1"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTOTPWords(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Date(2005, 3, 18, 1, 58, 29, 0, time.UTC)
+
+	digits := []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}
+	words := make([]string, 2048)
+	for i := range words {
+		words[i] = string(rune('a'+i%26)) + string(rune('a'+i/26%26)) + string(rune('a'+i/676))
+	}
+
+	tests := []struct {
+		Name      string
+		Wordlist  []string
+		WordCount int
+		Words     []string
+	}{
+		// digits are emitted least significant first, 081804 reversed
+		{"Decimal", digits, 6, []string{"4", "0", "8", "1", "8", "0"}},
+		{"Decimal Max", digits, 9, []string{"4", "0", "8", "1", "8", "0", "7", "0", "9"}},
+		{"Decimal Too Many", digits, 10, nil},
+		{"Wordlist", words, 2, []string{words[907081804%2048], words[907081804/2048%2048]}},
+		{"Wordlist Too Many", words, 3, nil},
+		{"Single Word List", []string{"a"}, 1, nil},
+		{"No Words", words, 0, nil},
+	}
+
+	if value := truncate(sha1.New, key, 0x23523EC); value != 907081804 {
+		t.Fatalf("Unexpected truncated value %d", value)
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := TOTPWords(key, now, test.Wordlist, test.WordCount)
+			if !reflect.DeepEqual(w, test.Words) {
+				t.Errorf("Words did not match. Expected %v and got %v.\n", test.Words, w)
+			}
+		})
+	}
+}